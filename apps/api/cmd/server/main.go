@@ -1,9 +1,11 @@
 package main
 
 import (
+	"io"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	// Embed timezone database so it works without system tzdata.
 	_ "time/tzdata"
@@ -13,18 +15,43 @@ import (
 	"github.com/akornatskyy/scheduler/internal/infrastructure/cron"
 	"github.com/akornatskyy/scheduler/internal/infrastructure/http"
 	"github.com/akornatskyy/scheduler/internal/infrastructure/postgres"
+	"github.com/akornatskyy/scheduler/internal/infrastructure/shell"
 )
 
 func main() {
 	log.Printf("starting scheduler version %s...", domain.Version)
 
 	dsn := os.Getenv("DSN")
+	runners := map[string]domain.Runner{
+		domain.ActionTypeHTTP: http.NewRunner(),
+		domain.ActionTypeSQL:  postgres.NewRunner(),
+	}
+	// Local commands run on the scheduler host, so they are opt-in.
+	if os.Getenv("EXEC") == "enabled" {
+		runners[domain.ActionTypeExec] = shell.NewRunner()
+	}
+	// Workflow steps run by the other runners.
+	runners[domain.ActionTypeWorkflow] = core.NewWorkflowRunner(runners)
 	service := &core.Service{
 		Repository: postgres.NewRepository(dsn),
 		Scheduler:  cron.New(),
-		Runners: map[string]domain.Runner{
-			"HTTP": http.NewRunner(),
-		},
+		Runners:    runners,
+	}
+	if v := os.Getenv("WORKERS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			log.Fatalf("ERR: WORKERS: %s", err)
+		}
+		service.Workers = n
+	}
+	// In leader mode only the elected instance fires jobs.
+	if os.Getenv("MODE") == "leader" {
+		name, err := os.Hostname()
+		if err != nil {
+			log.Fatalf("ERR: %s", err)
+		}
+		service.Elector = postgres.NewElector(dsn, name)
+		service.Scheduler = cron.NewLeader(service.Elector)
 	}
 
 	subscriber := postgres.NewSubscriber(dsn)
@@ -47,6 +74,13 @@ func main() {
 	server.Stop()
 	subscriber.Stop()
 	service.Stop()
+	for _, r := range runners {
+		if closer, ok := r.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				log.Printf("WARN: failed to close runner: %v", err)
+			}
+		}
+	}
 
 	log.Println("done")
 }
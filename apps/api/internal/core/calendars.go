@@ -0,0 +1,87 @@
+package core
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+func (s *Service) ListCalendars() ([]*domain.CalendarItem, error) {
+	return s.Repository.ListCalendars()
+}
+
+func (s *Service) CreateCalendar(c *domain.Calendar) error {
+	if err := domain.ValidateCalendar(c); err != nil {
+		return err
+	}
+	if c.ID == "" {
+		c.ID = domain.NewID()
+	}
+	return s.Repository.CreateCalendar(c)
+}
+
+func (s *Service) RetrieveCalendar(id string) (*domain.Calendar, error) {
+	if err := domain.ValidateID(id); err != nil {
+		return nil, err
+	}
+	return s.Repository.RetrieveCalendar(id)
+}
+
+func (s *Service) UpdateCalendar(c *domain.Calendar) error {
+	if err := domain.ValidateCalendar(c); err != nil {
+		return err
+	}
+	return s.Repository.UpdateCalendar(c)
+}
+
+func (s *Service) DeleteCalendar(id string) error {
+	if err := domain.ValidateID(id); err != nil {
+		return err
+	}
+	return s.Repository.DeleteCalendar(id)
+}
+
+// validateCalendars checks the attached calendars exist.
+func (s *Service) validateCalendars(ids []string) error {
+	for _, id := range ids {
+		if _, err := s.Repository.RetrieveCalendar(id); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return domain.UnknownCalendarError(id)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// blackout returns the name of a calendar of the job or its collection
+// the scheduled tick falls in, if any. Runs on demand are not affected.
+func (s *Service) blackout(j *domain.JobDefinition, scheduled time.Time) string {
+	c, err := s.Repository.RetrieveCollection(j.CollectionID)
+	if err != nil {
+		log.Printf("WARN: job %s: %s", j.ID, err)
+		return ""
+	}
+	if len(c.Calendars) == 0 && len(j.Calendars) == 0 {
+		return ""
+	}
+	jc := *j
+	jc.Inherit(c)
+	sched, err := domain.JobSchedule(&jc)
+	if err != nil || !domain.IsTick(sched, scheduled) {
+		return ""
+	}
+	for _, id := range append(c.Calendars, j.Calendars...) {
+		cal, err := s.Repository.RetrieveCalendar(id)
+		if err != nil {
+			log.Printf("WARN: job %s: calendar %s: %s", j.ID, id, err)
+			continue
+		}
+		if cal.Excludes(scheduled) {
+			return cal.Name
+		}
+	}
+	return ""
+}
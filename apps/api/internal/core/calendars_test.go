@@ -0,0 +1,50 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+func TestRunQueuedBlackout(t *testing.T) {
+	holidays := &domain.Calendar{
+		CalendarItem: domain.CalendarItem{ID: "holidays", Name: "Holidays"},
+		Dates:        []string{"2024-12-25"},
+	}
+	var testcases = []struct {
+		name      string
+		scheduled time.Time
+		skipped   bool
+	}{
+		{"tick", time.Date(2024, 12, 25, 8, 0, 0, 0, time.UTC), true},
+		{"on demand", time.Date(2024, 12, 25, 8, 0, 1, 0, time.UTC), false},
+		{"other date", time.Date(2024, 12, 24, 8, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newMockRepository(newTestJob("daily", "0 8 * * *"))
+			r.collection.Calendars = []string{holidays.ID}
+			r.calendars = append(r.calendars, holidays)
+			// no runners, so a run that is not skipped ends up early
+			s := &Service{Repository: r}
+
+			s.runQueued(&domain.JobRun{JobID: "daily", Scheduled: tt.scheduled})
+
+			if !tt.skipped {
+				if len(r.history) != 0 {
+					t.Errorf("unexpected history: %v", r.history[0])
+				}
+				return
+			}
+			if len(r.history) != 1 {
+				t.Fatalf("expected history, got: %d", len(r.history))
+			}
+			jh := r.history[0]
+			if jh.Status != domain.JobHistoryStatusSkipped ||
+				*jh.Message != "blackout calendar: Holidays" {
+				t.Errorf("got: %d, %s", jh.Status, *jh.Message)
+			}
+		})
+	}
+}
@@ -7,7 +7,7 @@ func (s *Service) ListCollections() ([]*domain.CollectionItem, error) {
 }
 
 func (s *Service) CreateCollection(c *domain.Collection) error {
-	if err := domain.ValidateCollection(c); err != nil {
+	if err := s.validateCollection(c); err != nil {
 		return err
 	}
 	if c.ID == "" {
@@ -24,7 +24,7 @@ func (s *Service) RetrieveCollection(id string) (*domain.Collection, error) {
 }
 
 func (s *Service) UpdateCollection(c *domain.Collection) error {
-	if err := domain.ValidateCollection(c); err != nil {
+	if err := s.validateCollection(c); err != nil {
 		return err
 	}
 	return s.Repository.UpdateCollection(c)
@@ -36,3 +36,10 @@ func (s *Service) DeleteCollection(id string) error {
 	}
 	return s.Repository.DeleteCollection(id)
 }
+
+func (s *Service) validateCollection(c *domain.Collection) error {
+	if err := domain.ValidateCollection(c); err != nil {
+		return err
+	}
+	return s.validateCalendars(c.Calendars)
+}
@@ -13,6 +13,16 @@ func (s *Service) ListJobHistory(id string) ([]*domain.JobHistory, error) {
 	return s.Repository.ListJobHistory(id)
 }
 
+func (s *Service) RetrieveJobHistory(jobID, id string) (*domain.JobHistory, error) {
+	if err := domain.ValidateID(jobID); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateID(id); err != nil {
+		return nil, err
+	}
+	return s.Repository.RetrieveJobHistory(jobID, id)
+}
+
 func (s *Service) DeleteJobHistory(id string, before time.Time) error {
 	if err := domain.ValidateID(id); err != nil {
 		return err
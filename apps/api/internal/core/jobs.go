@@ -3,6 +3,7 @@ package core
 
 import (
 	"log"
+	"time"
 
 	"github.com/akornatskyy/scheduler/internal/domain"
 )
@@ -66,33 +67,59 @@ func (s *Service) RunJob(id string) error {
 	if err := domain.ValidateID(id); err != nil {
 		return err
 	}
-	job, err := s.Repository.RetrieveJob(id)
-	if err != nil {
+	if _, err := s.Repository.RetrieveJob(id); err != nil {
 		return err
 	}
-
-	go s.OnRunJob(job)
-	return nil
+	return s.enqueue(id, time.Now().UTC())
 }
 
 func (s *Service) validateJobDefinition(job *domain.JobDefinition) error {
 	if err := domain.ValidateJobDefinition(job); err != nil {
 		return err
 	}
+	if s.Runners[domain.ActionTypeExec] == nil && job.Action.Runs(domain.ActionTypeExec) {
+		return domain.ErrExecDisabled
+	}
+	if err := s.validateCalendars(job.Calendars); err != nil {
+		return err
+	}
+	if err := s.validateTriggers(job); err != nil {
+		return err
+	}
 	variables, err := s.mapVariables(job.CollectionID)
 	if err != nil {
 		return err
 	}
-	req, err := job.Action.Request.Transpose(variables)
+	a, err := job.Action.Transpose(variables)
 	if err != nil {
 		return err
 	}
-	if err := domain.ValidateURI(req.URI); err != nil {
+	if err := validateRequestURI(a); err != nil {
 		return err
 	}
+	if a.Workflow == nil {
+		return nil
+	}
+	for _, step := range a.Workflow.Steps {
+		sa, err := step.Action.Transpose(variables)
+		if err != nil {
+			return err
+		}
+		if err := validateRequestURI(sa); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// validateRequestURI checks the transposed URI of an HTTP action.
+func validateRequestURI(a *domain.Action) error {
+	if a.Request == nil {
+		return nil
+	}
+	return domain.ValidateURI(a.Request.URI)
+}
+
 func (s *Service) resetLeftOverJobs() {
 	jobs, err := s.Repository.ListLeftOverJobs()
 	if err != nil {
@@ -2,13 +2,20 @@ package core
 
 import (
 	"context"
+	"errors"
 	"log"
 	"time"
 
 	"github.com/akornatskyy/scheduler/internal/domain"
 )
 
-func (s *Service) OnRunJob(j *domain.JobDefinition) {
+// pollInterval is how often a running job checks whether it is replaced.
+const pollInterval = time.Second
+
+var errReplaced = errors.New("replaced by a newer run")
+
+// OnRunJob runs the job for the scheduled tick.
+func (s *Service) OnRunJob(j *domain.JobDefinition, scheduled time.Time) {
 	log.Printf("attempting to run job %s", j.ID)
 	a := j.Action
 	runner := s.Runners[a.Type]
@@ -22,65 +29,183 @@ func (s *Service) OnRunJob(j *domain.JobDefinition) {
 		p = domain.DefaultRetryPolicy
 	}
 
-	err := s.Repository.AcquireJob(j.ID, time.Duration(p.Deadline))
+	started := time.Now().UTC()
+	generation, err := s.Repository.AcquireJob(
+		j.ID, time.Duration(p.Deadline), j.Concurrency)
 	if err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			s.skipJob(j, scheduled, started, "previous run is still in progress")
+			return
+		}
 		log.Printf("WARN: acquire job %s: %s", j.ID, err)
 		return
 	}
 
 	jh := &domain.JobHistory{
-		JobID:   j.ID,
-		Action:  a.Type,
-		Started: time.Now().UTC(),
+		JobID:     j.ID,
+		Action:    a.Type,
+		Scheduled: &scheduled,
+		Started:   started,
 	}
 
-	attempt := 0
-
+	var res *domain.RunResult
 	a, err = s.transposeAction(j)
 	if err == nil {
 		ctx, cancel := context.WithTimeout(s.ctx, time.Duration(p.Deadline))
 		defer cancel()
-
-	loop:
-		for {
-			err = runner.Run(ctx, a)
-			if err == nil || attempt == p.RetryCount {
-				break
-			}
-			if re, ok := err.(*domain.RunError); ok {
-				switch re.Code {
-				// https://developer.mozilla.org/en-US/docs/Web/HTTP/Status#Client_error_responses
-				// TODO: add all client error responses (4XX) as unrecoverable?
-				case 400, 401, 403, 404, 422:
-					break loop
-				}
-			}
-			select {
-			case <-ctx.Done():
-				err = ctx.Err()
-				if err != nil {
-					break loop
-				}
-			case <-time.After(time.Duration(p.RetryInterval)):
-			}
-			attempt++
+		if j.Concurrency.Replace() {
+			var stop context.CancelCauseFunc
+			ctx, stop = context.WithCancelCause(ctx)
+			defer stop(nil)
+			go s.watchReplaced(ctx, stop, j.ID, generation)
+		}
+		res, err = s.runAttempts(ctx, runner, a, p, jh)
+		if err != nil && errors.Is(context.Cause(ctx), errReplaced) {
+			err = errReplaced
 		}
 	}
 
 	jh.Finished = time.Now().UTC()
-	jh.RetryCount = attempt
+	if len(jh.Attempts) > 0 {
+		jh.RetryCount = len(jh.Attempts) - 1
+	}
+	if res != nil {
+		jh.Response = res.Response
+	}
 	if err != nil {
 		jh.Status = domain.JobHistoryStatusFailed
-		msg := err.Error()
-		jh.Message = &msg
+		jh.Message = message(err.Error())
 	} else {
 		jh.Status = domain.JobHistoryStatusCompleted
+		if res != nil && res.Message != "" {
+			jh.Message = message(res.Message)
+		}
 	}
 
 	log.Printf("job %s: %s", j.ID, jh.Status)
 	if err = s.Repository.AddJobHistory(jh); err != nil {
 		log.Printf("ERR: job %s: %s", j.ID, err)
 	}
+	s.triggerDependents(j, jh)
+}
+
+// skipJob records the run is skipped for the reason.
+func (s *Service) skipJob(
+	j *domain.JobDefinition, scheduled, started time.Time, reason string,
+) {
+	log.Printf("job %s: skipped, %s", j.ID, reason)
+	jh := &domain.JobHistory{
+		JobID:     j.ID,
+		Action:    j.Action.Type,
+		Scheduled: &scheduled,
+		Started:   started,
+		Finished:  started,
+		Status:    domain.JobHistoryStatusSkipped,
+		Message:   message(reason),
+	}
+	if err := s.Repository.AddJobHistory(jh); err != nil {
+		log.Printf("ERR: job %s: %s", j.ID, err)
+	}
+}
+
+// watchReplaced cancels the run once a newer one of the job is acquired.
+func (s *Service) watchReplaced(
+	ctx context.Context, stop context.CancelCauseFunc, id string, generation int,
+) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			st, err := s.Repository.RetrieveJobStatus(id)
+			if err != nil {
+				log.Printf("WARN: job %s: %s", id, err)
+				continue
+			}
+			if st.Generation != generation {
+				log.Printf("job %s: %s", id, errReplaced)
+				stop(errReplaced)
+				return
+			}
+		}
+	}
+}
+
+// disableOneOff disables the job with one-off schedule after the run
+// for its tick, so it is not run again.
+func (s *Service) disableOneOff(j *domain.JobDefinition, scheduled time.Time) {
+	if !domain.IsOneOff(j.Schedule) {
+		return
+	}
+	sched, err := domain.ParseSchedule(j.Schedule, "")
+	if err != nil || !domain.IsTick(sched, scheduled) {
+		// e.g. run on demand
+		return
+	}
+	current, err := s.Repository.RetrieveJob(j.ID)
+	if err != nil {
+		log.Printf("ERR: job %s: %s", j.ID, err)
+		return
+	}
+	// the job might be rescheduled meanwhile
+	if current.State != domain.JobStateEnabled || current.Schedule != j.Schedule {
+		return
+	}
+	current.State = domain.JobStateDisabled
+	if err = s.Repository.UpdateJob(current); err != nil {
+		log.Printf("WARN: disable job %s: %s", j.ID, err)
+		return
+	}
+	log.Printf("job %s: disabled after one-off run", j.ID)
+}
+
+// runAttempts runs the action until it succeeds, fails with unrecoverable
+// error or retries are exhausted, recording each attempt in job history.
+func (s *Service) runAttempts(
+	ctx context.Context, runner domain.Runner, a *domain.Action,
+	p *domain.RetryPolicy, jh *domain.JobHistory,
+) (*domain.RunResult, error) {
+	var delay time.Duration
+	for attempt := 0; ; attempt++ {
+		at := &domain.JobAttempt{
+			Started: time.Now().UTC(),
+			Delay:   domain.Duration(delay),
+		}
+		res, err := runner.Run(ctx, a)
+		at.Finished = time.Now().UTC()
+		if res != nil {
+			at.Code = res.Code
+		}
+		if err != nil {
+			at.Message = message(err.Error())
+		}
+		jh.Attempts = append(jh.Attempts, at)
+
+		if err == nil || attempt == p.RetryCount || !p.Retryable(err) {
+			return res, err
+		}
+		now := time.Now()
+		var ok bool
+		if delay, ok = domain.RetryAfter(err, now); !ok {
+			delay = p.Interval(attempt + 1)
+		}
+		if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+			// the next attempt is not going to start before deadline
+			return res, err
+		}
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func message(s string) *string {
+	msg := domain.TruncateMessage(s, domain.MaxMessageLength)
+	return &msg
 }
 
 func (s *Service) transposeAction(j *domain.JobDefinition) (*domain.Action, error) {
@@ -90,14 +215,5 @@ func (s *Service) transposeAction(j *domain.JobDefinition) (*domain.Action, erro
 	}
 	variables["CollectionID"] = j.CollectionID
 	variables["JobID"] = j.ID
-	a := j.Action
-	req, err := a.Request.Transpose(variables)
-	if err != nil {
-		return nil, err
-	}
-	return &domain.Action{
-		Type:        a.Type,
-		Request:     req,
-		RetryPolicy: a.RetryPolicy,
-	}, nil
+	return j.Action.Transpose(variables)
 }
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+// failingRunner fails with the errors in turn, then succeeds.
+type failingRunner struct {
+	errs []error
+	runs int
+}
+
+func (r *failingRunner) Run(ctx context.Context, a *domain.Action) (*domain.RunResult, error) {
+	r.runs++
+	if len(r.errs) == 0 {
+		return &domain.RunResult{Code: 200}, nil
+	}
+	err := r.errs[0]
+	r.errs = r.errs[1:]
+	return &domain.RunResult{Code: err.(*domain.RunError).Code}, err
+}
+
+func TestRunAttempts(t *testing.T) {
+	unavailable := &domain.RunError{Code: 503}
+	notFound := &domain.RunError{Code: 404}
+	p := &domain.RetryPolicy{
+		RetryCount:    3,
+		RetryInterval: domain.Duration(10 * time.Millisecond),
+		Backoff:       domain.BackoffExponential,
+	}
+	var testcases = []struct {
+		name   string
+		errs   []error
+		err    error
+		codes  []int
+		delays []time.Duration
+	}{
+		{"succeeded", nil, nil, []int{200}, []time.Duration{0}},
+		{
+			"retried", []error{unavailable, unavailable}, nil,
+			[]int{503, 503, 200},
+			[]time.Duration{0, 10 * time.Millisecond, 20 * time.Millisecond},
+		},
+		{
+			"exhausted", []error{unavailable, unavailable, unavailable, unavailable},
+			unavailable,
+			[]int{503, 503, 503, 503},
+			[]time.Duration{
+				0, 10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond,
+			},
+		},
+		{"unrecoverable", []error{notFound}, notFound, []int{404}, []time.Duration{0}},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{}
+			runner := &failingRunner{errs: tt.errs}
+			jh := &domain.JobHistory{}
+
+			_, err := s.runAttempts(context.Background(), runner, &domain.Action{}, p, jh)
+
+			if err != tt.err {
+				t.Errorf("got: %v, expected: %v", err, tt.err)
+			}
+			var codes []int
+			var delays []time.Duration
+			for _, at := range jh.Attempts {
+				codes = append(codes, at.Code)
+				delays = append(delays, time.Duration(at.Delay))
+			}
+			if !reflect.DeepEqual(codes, tt.codes) {
+				t.Errorf("codes, got: %v, expected: %v", codes, tt.codes)
+			}
+			if !reflect.DeepEqual(delays, tt.delays) {
+				t.Errorf("delays, got: %v, expected: %v", delays, tt.delays)
+			}
+		})
+	}
+}
+
+func TestRunAttemptsDeadline(t *testing.T) {
+	s := &Service{}
+	runner := &failingRunner{errs: []error{&domain.RunError{Code: 503}}}
+	p := &domain.RetryPolicy{
+		RetryCount:    3,
+		RetryInterval: domain.Duration(time.Minute),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	jh := &domain.JobHistory{}
+
+	_, err := s.runAttempts(ctx, runner, &domain.Action{}, p, jh)
+
+	if err == nil || runner.runs != 1 || len(jh.Attempts) != 1 {
+		t.Errorf("expected one attempt before deadline, got: %d, %v",
+			runner.runs, err)
+	}
+}
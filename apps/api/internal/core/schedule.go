@@ -0,0 +1,79 @@
+package core
+
+import (
+	"slices"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+// DefaultPreviewCount is a number of fire times previewed by default.
+const DefaultPreviewCount = 5
+
+func (s *Service) PreviewSchedule(spec, tz string, count int) ([]time.Time, error) {
+	if err := domain.ValidateSchedulePreview(spec, tz, count); err != nil {
+		return nil, err
+	}
+	sched, err := domain.ParseSchedule(spec, tz)
+	if err != nil {
+		return nil, err
+	}
+	return domain.Upcoming(sched, time.Now(), count), nil
+}
+
+// ListUpcomingRuns expands schedules of enabled jobs in enabled collections
+// into planned runs ordered by time.
+func (s *Service) ListUpcomingRuns(q *domain.UpcomingQuery) ([]*domain.PlannedRun, error) {
+	if err := domain.ValidateUpcomingQuery(q); err != nil {
+		return nil, err
+	}
+	jobs, err := s.Repository.ListEnabledJobs(q.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]*domain.PlannedRun, 0)
+	for _, j := range jobs {
+		sched, err := domain.JobSchedule(j)
+		if err != nil {
+			return nil, err
+		}
+		limit := domain.MaxUpcomingRuns - len(runs) + 1
+		for _, t := range domain.Between(sched, q.From, q.To, limit) {
+			runs = append(runs, &domain.PlannedRun{
+				JobID:        j.ID,
+				CollectionID: j.CollectionID,
+				Scheduled:    t,
+			})
+		}
+		if len(runs) > domain.MaxUpcomingRuns {
+			return nil, domain.ErrTooManyRuns
+		}
+	}
+	slices.SortStableFunc(runs, func(a, b *domain.PlannedRun) int {
+		return a.Scheduled.Compare(b.Scheduled)
+	})
+	return runs, nil
+}
+
+// CountUpcomingRuns counts planned runs of enabled jobs in enabled
+// collections per minute, unlike the list the runs are not limited.
+func (s *Service) CountUpcomingRuns(q *domain.UpcomingQuery) ([]*domain.HistogramBucket, error) {
+	if err := domain.ValidateUpcomingQuery(q); err != nil {
+		return nil, err
+	}
+	jobs, err := s.Repository.ListEnabledJobs(q.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[time.Time]int)
+	for _, j := range jobs {
+		sched, err := domain.JobSchedule(j)
+		if err != nil {
+			return nil, err
+		}
+		for t := sched.Next(q.From); !t.IsZero() && !t.After(q.To); t = sched.Next(t) {
+			counts[t.UTC().Truncate(time.Minute)]++
+		}
+	}
+	return domain.Histogram(counts), nil
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+func TestCountUpcomingRuns(t *testing.T) {
+	s := &Service{Repository: newMockRepository(
+		newTestJob("every-second", "@every 1s"),
+		newTestJob("every-20m", "*/20 * * * *"),
+	)}
+	from := time.Date(2024, 3, 29, 8, 0, 0, 0, time.UTC)
+	q := &domain.UpcomingQuery{
+		From: from,
+		To:   from.Add(3 * time.Hour),
+		Mode: domain.UpcomingModeHistogram,
+	}
+
+	buckets, err := s.CountUpcomingRuns(q)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 181 {
+		t.Fatalf("buckets, got: %d, expected: 181", len(buckets))
+	}
+	total := 0
+	for _, b := range buckets {
+		total += b.Count
+	}
+	if total != 3*3600+9 {
+		t.Errorf("total, got: %d, expected: %d", total, 3*3600+9)
+	}
+	if b := buckets[20]; !b.Minute.Equal(from.Add(20*time.Minute)) || b.Count != 61 {
+		t.Errorf("bucket, got: %s %d", b.Minute, b.Count)
+	}
+	q.Mode = domain.UpcomingModeList
+	if _, err := s.ListUpcomingRuns(q); err != domain.ErrTooManyRuns {
+		t.Errorf("list, got err: %v", err)
+	}
+}
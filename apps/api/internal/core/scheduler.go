@@ -2,6 +2,7 @@ package core
 
 import (
 	"log"
+	"time"
 
 	"github.com/akornatskyy/scheduler/internal/domain"
 )
@@ -11,33 +12,46 @@ func (s *Service) scheduleJobs() error {
 	if err != nil {
 		return err
 	}
-	scheduled := s.Scheduler.ListIDs()
+	scheduled := make(map[string]bool)
+	for _, id := range s.Scheduler.ListIDs() {
+		scheduled[id] = true
+	}
 	added := make(map[string]bool)
 	n := 0
-	for _, c := range collections {
-		jobs, err := s.Repository.ListJobs(c.ID, []string{})
+	for _, item := range collections {
+		jobs, err := s.Repository.ListJobs(item.ID, []string{})
 		if err != nil {
 			return err
 		}
+		var c *domain.Collection
 		for _, j := range jobs {
-			if c.State != domain.CollectionStateEnabled ||
+			if item.State != domain.CollectionStateEnabled ||
 				j.State != domain.JobStateEnabled {
 				s.Scheduler.Remove(j.ID)
 				continue
 			}
+			if c == nil {
+				if c, err = s.Repository.RetrieveCollection(item.ID); err != nil {
+					return err
+				}
+			}
 			j, err := s.Repository.RetrieveJob(j.ID)
 			if err != nil {
 				return err
 			}
-			if err = s.Scheduler.Add(j); err != nil {
+			if err = s.scheduleJob(j, c); err != nil {
 				return err
 			}
+			if !scheduled[j.ID] {
+				// ticks might be missed while no instance was up
+				s.catchUp(j)
+			}
 			added[j.ID] = true
 			n++
 		}
 	}
 	// remove orphaned jobs if any
-	for _, id := range scheduled {
+	for id := range scheduled {
 		if !added[id] {
 			s.Scheduler.Remove(id)
 		}
@@ -45,3 +59,72 @@ func (s *Service) scheduleJobs() error {
 	log.Printf("scheduled %d jobs", n)
 	return nil
 }
+
+// scheduleJob adds the job to scheduler unless it is expired, the job
+// inherits the collection timezone and jitter unless it has own ones.
+func (s *Service) scheduleJob(j *domain.JobDefinition, c *domain.Collection) error {
+	j.Inherit(c)
+	if j.Expired(time.Now()) {
+		s.Scheduler.Remove(j.ID)
+		return nil
+	}
+	return s.Scheduler.Add(j)
+}
+
+// catchUp runs the job for ticks missed since the job last run or update,
+// up to domain.MaxMisfireAge ago, according to the job misfire policy.
+func (s *Service) catchUp(j *domain.JobDefinition) {
+	if j.Misfire == nil || j.Misfire.Policy == domain.MisfireSkip {
+		return
+	}
+	st, err := s.Repository.RetrieveJobStatus(j.ID)
+	if err != nil {
+		log.Printf("WARN: catch up job %s: %s", j.ID, err)
+		return
+	}
+	since := j.Updated
+	if st.LastRun != nil && st.LastRun.After(since) {
+		since = *st.LastRun
+	}
+	sched, err := domain.JobSchedule(j)
+	if err != nil {
+		log.Printf("WARN: catch up job %s: %s", j.ID, err)
+		return
+	}
+	ticks := j.Misfire.Missed(sched, since, time.Now())
+	if len(ticks) == 0 {
+		return
+	}
+	log.Printf("job %s: catching up %d missed runs", j.ID, len(ticks))
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for _, t := range ticks {
+			// a missed tick is enqueued once across instances, however
+			// it must not be skipped due to a run of a previous one
+			if !s.awaitIdle(j.ID) || s.ctx.Err() != nil {
+				return
+			}
+			s.onScheduledJob(j, t)
+		}
+	}()
+}
+
+// awaitIdle waits until the job is neither running nor pending.
+func (s *Service) awaitIdle(id string) bool {
+	for {
+		st, err := s.Repository.RetrieveJobStatus(id)
+		if err != nil {
+			log.Printf("WARN: job %s: %s", id, err)
+			return false
+		}
+		if !st.Running && st.Pending == 0 {
+			return true
+		}
+		select {
+		case <-s.ctx.Done():
+			return false
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/akornatskyy/scheduler/internal/domain"
 )
@@ -13,9 +14,15 @@ type Service struct {
 	Repository domain.Repository
 	Scheduler  domain.Scheduler
 	Runners    map[string]domain.Runner
-	ctx        context.Context
-	cancel     context.CancelFunc
-	variables  map[string]string
+	// Elector is set in leader mode only.
+	Elector domain.Elector
+	// Workers is the number of workers running queued jobs.
+	Workers   int
+	wg        sync.WaitGroup
+	wake      chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+	variables map[string]string
 }
 
 func (s *Service) Start() {
@@ -25,7 +32,8 @@ func (s *Service) Start() {
 	s.variables = mapEnviron()
 
 	s.resetLeftOverJobs()
-	s.Scheduler.SetRunner(s.OnRunJob)
+	s.startWorkers()
+	s.Scheduler.SetRunner(s.onScheduledJob)
 	s.Scheduler.Start()
 }
 
@@ -33,6 +41,7 @@ func (s *Service) Stop() {
 	log.Println("canceling all running jobs...")
 	s.cancel()
 	s.Scheduler.Stop()
+	s.wg.Wait()
 	if err := s.Repository.Close(); err != nil {
 		log.Printf("WARN: failed to close repository: %v", err)
 	}
@@ -42,6 +51,15 @@ func (s *Service) Health() error {
 	return s.Repository.Ping()
 }
 
+// Leader returns a name of the current leader instance, empty unless
+// in leader mode.
+func (s *Service) Leader() (string, error) {
+	if s.Elector == nil {
+		return "", nil
+	}
+	return s.Elector.Leader()
+}
+
 func mapEnviron() map[string]string {
 	variables := make(map[string]string)
 	for _, e := range os.Environ() {
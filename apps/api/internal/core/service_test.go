@@ -0,0 +1,126 @@
+package core
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+// mockRepository implements the repository methods the tests rely on, the
+// others panic.
+type mockRepository struct {
+	domain.Repository
+	mu         sync.Mutex
+	jobs       []*domain.JobDefinition
+	collection *domain.Collection
+	calendars  []*domain.Calendar
+	runs       []*domain.JobRun
+	history    []*domain.JobHistory
+}
+
+func newMockRepository(jobs ...*domain.JobDefinition) *mockRepository {
+	return &mockRepository{
+		jobs: jobs,
+		collection: &domain.Collection{
+			CollectionItem: domain.CollectionItem{
+				ID:    "my-app",
+				State: domain.CollectionStateEnabled,
+			},
+		},
+	}
+}
+
+func newTestJob(id, schedule string) *domain.JobDefinition {
+	return &domain.JobDefinition{
+		JobItem: domain.JobItem{
+			ID:           id,
+			Name:         id,
+			CollectionID: "my-app",
+			State:        domain.JobStateEnabled,
+			Schedule:     schedule,
+		},
+		Action: &domain.Action{
+			Type:    domain.ActionTypeHTTP,
+			Request: &domain.HTTPRequest{URI: "https://example.com/" + id},
+		},
+	}
+}
+
+func (r *mockRepository) RetrieveCollection(id string) (*domain.Collection, error) {
+	return r.collection, nil
+}
+
+func (r *mockRepository) MapVariables(collectionID string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+func (r *mockRepository) RetrieveCalendar(id string) (*domain.Calendar, error) {
+	for _, c := range r.calendars {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockRepository) RetrieveJob(id string) (*domain.JobDefinition, error) {
+	for _, j := range r.jobs {
+		if j.ID == id {
+			return j, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockRepository) ListEnabledJobs(collectionID string) ([]*domain.JobDefinition, error) {
+	var jobs []*domain.JobDefinition
+	for _, j := range r.jobs {
+		if j.State == domain.JobStateEnabled {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *mockRepository) ListJobTriggers() (map[string][]*domain.JobTrigger, error) {
+	triggers := make(map[string][]*domain.JobTrigger)
+	for _, j := range r.jobs {
+		if len(j.Triggers) > 0 {
+			triggers[j.ID] = j.Triggers
+		}
+	}
+	return triggers, nil
+}
+
+func (r *mockRepository) ListDependentJobs(upstreamID string) (map[string][]*domain.JobTrigger, error) {
+	triggers := make(map[string][]*domain.JobTrigger)
+	for _, j := range r.jobs {
+		if j.State == domain.JobStateEnabled &&
+			slices.ContainsFunc(j.Triggers, func(t *domain.JobTrigger) bool {
+				return t.JobID == upstreamID
+			}) {
+			triggers[j.ID] = j.Triggers
+		}
+	}
+	return triggers, nil
+}
+
+func (r *mockRepository) EnqueueJobRun(id string, scheduled time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, &domain.JobRun{JobID: id, Scheduled: scheduled})
+	return nil
+}
+
+func (r *mockRepository) FinishJobRun(run *domain.JobRun) error {
+	return nil
+}
+
+func (r *mockRepository) AddJobHistory(jh *domain.JobHistory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.history = append(r.history, jh)
+	return nil
+}
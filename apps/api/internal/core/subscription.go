@@ -59,7 +59,7 @@ func (s *Service) onCollectionUpdate(id string) error {
 				return err
 			}
 			if j.State == domain.JobStateEnabled {
-				if err := s.Scheduler.Add(j); err != nil {
+				if err := s.scheduleJob(j, c); err != nil {
 					log.Printf("WARN: failed to add job %s: %v", j.ID, err)
 				}
 			}
@@ -79,7 +79,7 @@ func (s *Service) onJobInsert(id string) error {
 			return err
 		}
 		if c.State == domain.CollectionStateEnabled {
-			if err := s.Scheduler.Add(j); err != nil {
+			if err := s.scheduleJob(j, c); err != nil {
 				log.Printf("WARN: failed to add job %s: %v", j.ID, err)
 			}
 		}
@@ -99,7 +99,7 @@ func (s *Service) onJobUpdate(id string) error {
 			return err
 		}
 		if c.State == domain.CollectionStateEnabled {
-			if err := s.Scheduler.Add(j); err != nil {
+			if err := s.scheduleJob(j, c); err != nil {
 				log.Printf("failed to add job %s: %v", j.ID, err)
 			}
 		}
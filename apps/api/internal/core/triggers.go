@@ -0,0 +1,71 @@
+package core
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+// validateTriggers checks the upstream jobs exist and the job triggers do
+// not form a cycle.
+func (s *Service) validateTriggers(job *domain.JobDefinition) error {
+	if len(job.Triggers) == 0 {
+		return nil
+	}
+	for _, t := range job.Triggers {
+		if _, err := s.Repository.RetrieveJob(t.JobID); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return domain.UnknownJobError(t.JobID)
+			}
+			return err
+		}
+	}
+	triggers, err := s.Repository.ListJobTriggers()
+	if err != nil {
+		return err
+	}
+	triggers[job.ID] = job.Triggers
+	// a new cycle passes through the job, so it is looked up among the
+	// upstream jobs
+	visited := make(map[string]bool)
+	pending := []string{job.ID}
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, t := range triggers[id] {
+			if t.JobID == job.ID {
+				return domain.TriggerCycleError(id)
+			}
+			if !visited[t.JobID] {
+				visited[t.JobID] = true
+				pending = append(pending, t.JobID)
+			}
+		}
+	}
+	return nil
+}
+
+// triggerDependents enqueues runs of enabled jobs triggered by the job run
+// outcome, a delayed run is not claimed until its scheduled time.
+func (s *Service) triggerDependents(j *domain.JobDefinition, jh *domain.JobHistory) {
+	triggers, err := s.Repository.ListDependentJobs(j.ID)
+	if err != nil {
+		log.Printf("WARN: job %s: list dependent jobs: %s", j.ID, err)
+		return
+	}
+	for id, tt := range triggers {
+		for _, t := range tt {
+			if t.JobID != j.ID || !t.Fires(jh.Status) {
+				continue
+			}
+			log.Printf("job %s: triggered by job %s %s", id, j.ID, jh.Status)
+			scheduled := jh.Finished.Add(time.Duration(t.Delay))
+			if err := s.enqueue(id, scheduled); err != nil {
+				log.Printf("ERR: enqueue job %s: %s", id, err)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+func withTriggers(j *domain.JobDefinition, upstream ...string) *domain.JobDefinition {
+	for _, id := range upstream {
+		j.Triggers = append(j.Triggers, &domain.JobTrigger{
+			JobID:   id,
+			Outcome: domain.TriggerCompleted,
+		})
+	}
+	return j
+}
+
+func TestValidateTriggers(t *testing.T) {
+	var testcases = []struct {
+		name     string
+		job      *domain.JobDefinition
+		expected error
+	}{
+		{"none", newTestJob("c", "@every 1m"), nil},
+		{"chain", withTriggers(newTestJob("c", ""), "b"), nil},
+		{"diamond", withTriggers(newTestJob("d", ""), "a", "b"), nil},
+		{"unknown", withTriggers(newTestJob("c", ""), "x"),
+			domain.UnknownJobError("x")},
+		{"self", withTriggers(newTestJob("a", ""), "a"),
+			domain.TriggerCycleError("a")},
+		{"direct", withTriggers(newTestJob("a", "@every 1m"), "b"),
+			domain.TriggerCycleError("b")},
+		{"indirect", withTriggers(newTestJob("a", "@every 1m"), "c"),
+			domain.TriggerCycleError("b")},
+	}
+	for _, tt := range testcases {
+		t.Run(tt.name, func(t *testing.T) {
+			// a <- b <- c
+			s := &Service{Repository: newMockRepository(
+				newTestJob("a", "@every 1m"),
+				withTriggers(newTestJob("b", ""), "a"),
+				withTriggers(newTestJob("c", ""), "b"),
+			)}
+
+			err := s.validateTriggers(tt.job)
+
+			if !reflect.DeepEqual(err, tt.expected) {
+				t.Errorf("got: %v, expected: %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateJobDefinitionTriggersOnly(t *testing.T) {
+	s := &Service{Repository: newMockRepository(newTestJob("upstream", "@every 1m"))}
+
+	if err := s.validateJobDefinition(
+		withTriggers(newTestJob("downstream", ""), "upstream")); err != nil {
+		t.Errorf("triggers only, got: %v", err)
+	}
+	if err := s.validateJobDefinition(newTestJob("unscheduled", "")); err == nil {
+		t.Error("neither schedule nor triggers, expected error")
+	}
+}
+
+func TestTriggerDependents(t *testing.T) {
+	finished := time.Date(2024, 3, 29, 8, 0, 0, 0, time.UTC)
+	failed := newTestJob("on-failed", "")
+	failed.Triggers = []*domain.JobTrigger{
+		{JobID: "up", Outcome: domain.TriggerFailed},
+	}
+	delayed := newTestJob("delayed", "@every 1h")
+	delayed.Triggers = []*domain.JobTrigger{
+		{JobID: "other", Outcome: domain.TriggerAny},
+		{JobID: "up", Outcome: domain.TriggerAny, Delay: domain.Duration(time.Minute)},
+	}
+	disabled := withTriggers(newTestJob("disabled", ""), "up")
+	disabled.State = domain.JobStateDisabled
+	var testcases = []struct {
+		status   domain.JobHistoryStatus
+		expected []*domain.JobRun
+	}{
+		{domain.JobHistoryStatusCompleted, []*domain.JobRun{
+			{JobID: "on-completed", Scheduled: finished},
+			{JobID: "delayed", Scheduled: finished.Add(time.Minute)},
+		}},
+		{domain.JobHistoryStatusFailed, []*domain.JobRun{
+			{JobID: "on-failed", Scheduled: finished},
+			{JobID: "delayed", Scheduled: finished.Add(time.Minute)},
+		}},
+		{domain.JobHistoryStatusSkipped, nil},
+	}
+	for _, tt := range testcases {
+		r := newMockRepository(
+			newTestJob("up", "@every 1m"),
+			withTriggers(newTestJob("on-completed", ""), "up"),
+			failed, delayed, disabled,
+		)
+		s := &Service{Repository: r}
+		jh := &domain.JobHistory{Status: tt.status, Finished: finished}
+
+		s.triggerDependents(r.jobs[0], jh)
+
+		sortRuns(r.runs)
+		sortRuns(tt.expected)
+		if !reflect.DeepEqual(r.runs, tt.expected) {
+			t.Errorf("%d: got: %v, expected: %v", tt.status, r.runs, tt.expected)
+		}
+	}
+}
+
+func sortRuns(runs []*domain.JobRun) {
+	slices.SortFunc(runs, func(a, b *domain.JobRun) int {
+		return strings.Compare(a.JobID, b.JobID)
+	})
+}
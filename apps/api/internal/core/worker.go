@@ -0,0 +1,91 @@
+package core
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+// DefaultWorkers is the number of workers per instance running queued jobs.
+const DefaultWorkers = 4
+
+// enqueue adds a run of the job for the scheduled tick to the queue. The
+// same tick enqueued by other instances is ignored.
+func (s *Service) enqueue(id string, scheduled time.Time) error {
+	err := s.Repository.EnqueueJobRun(id, scheduled)
+	if err != nil {
+		if errors.Is(err, domain.ErrDuplicateRun) {
+			log.Printf("job %s: tick %s is already enqueued",
+				id, scheduled.Format(time.RFC3339))
+			return nil
+		}
+		return err
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// onScheduledJob enqueues a run of the job fired by scheduler.
+func (s *Service) onScheduledJob(j *domain.JobDefinition, scheduled time.Time) {
+	if err := s.enqueue(j.ID, scheduled); err != nil {
+		log.Printf("ERR: enqueue job %s: %s", j.ID, err)
+	}
+}
+
+func (s *Service) startWorkers() {
+	n := s.Workers
+	if n <= 0 {
+		n = DefaultWorkers
+	}
+	s.wake = make(chan struct{}, 1)
+	for i := 0; i < n; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+	log.Printf("started %d workers", n)
+}
+
+// work claims queued runs until the service is stopped, it polls the
+// queue for runs enqueued by other instances.
+func (s *Service) work() {
+	defer s.wg.Done()
+	for s.ctx.Err() == nil {
+		r, err := s.Repository.ClaimJobRun()
+		if err == nil {
+			s.runQueued(r)
+			continue
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			log.Printf("WARN: claim job run: %s", err)
+		}
+		select {
+		case <-s.ctx.Done():
+		case <-s.wake:
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (s *Service) runQueued(r *domain.JobRun) {
+	defer func() {
+		if err := s.Repository.FinishJobRun(r); err != nil {
+			log.Printf("WARN: finish job %s run: %s", r.JobID, err)
+		}
+	}()
+	j, err := s.Repository.RetrieveJob(r.JobID)
+	if err != nil {
+		log.Printf("WARN: job %s: %s", r.JobID, err)
+		return
+	}
+	if name := s.blackout(j, r.Scheduled); name != "" {
+		s.skipJob(j, r.Scheduled, time.Now().UTC(), "blackout calendar: "+name)
+	} else {
+		s.OnRunJob(j, r.Scheduled)
+	}
+	s.disableOneOff(j, r.Scheduled)
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+	"github.com/akornatskyy/scheduler/internal/shared/jsonpath"
+)
+
+type workflowRunner struct {
+	runners map[string]domain.Runner
+}
+
+// NewWorkflowRunner returns a runner of workflow steps by the runners of
+// their action types.
+func NewWorkflowRunner(runners map[string]domain.Runner) domain.Runner {
+	return &workflowRunner{runners: runners}
+}
+
+// Run runs the workflow steps, the workflow status so far is available to
+// the steps as Status variable. The result is the one of the failed step,
+// otherwise of the last step run, the message lists outcomes of the steps.
+func (r *workflowRunner) Run(ctx context.Context, a *domain.Action) (*domain.RunResult, error) {
+	w := a.Workflow
+	variables := maps.Clone(w.Variables())
+	if variables == nil {
+		variables = make(map[string]string)
+	}
+	res := &domain.RunResult{}
+	lines := make([]string, 0, len(w.Steps))
+	var failed error
+	for _, step := range w.Steps {
+		if failed != nil && !step.Always {
+			lines = append(lines, step.Name+": skipped")
+			continue
+		}
+		variables["Status"] = "completed"
+		if failed != nil {
+			variables["Status"] = "failed"
+		}
+		ok, err := step.Holds(variables)
+		if err == nil && !ok {
+			lines = append(lines, step.Name+": skipped")
+			continue
+		}
+		var sr *domain.RunResult
+		if err == nil {
+			sr, err = r.runStep(ctx, step, variables)
+		}
+		if sr != nil && failed == nil {
+			res.Code, res.Response = sr.Code, sr.Response
+		}
+		if err != nil {
+			lines = append(lines, step.Name+": failed")
+			if failed == nil {
+				failed = fmt.Errorf("step %s: %w", step.Name, err)
+			}
+			continue
+		}
+		lines = append(lines, step.Name+": completed")
+	}
+	res.Message = strings.Join(lines, "\n")
+	return res, failed
+}
+
+// runStep runs the step action transposed with the variables, the same way
+// as a job action, and adds the step outputs to them. The request URI is
+// validated again since it might depend on outputs of the previous steps.
+func (r *workflowRunner) runStep(
+	ctx context.Context, step *domain.WorkflowStep, variables map[string]string,
+) (*domain.RunResult, error) {
+	a, err := step.Action.Transpose(variables)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRequestURI(a); err != nil {
+		return nil, err
+	}
+	runner := r.runners[a.Type]
+	if runner == nil {
+		return nil, fmt.Errorf("unsupported action type: %s", a.Type)
+	}
+	res, err := runner.Run(ctx, a)
+	if err != nil {
+		return res, err
+	}
+	if res == nil {
+		res = &domain.RunResult{}
+	}
+	for _, o := range step.Outputs {
+		v, err := extractOutput(o, res)
+		if err != nil {
+			return res, err
+		}
+		variables[o.Name] = v
+	}
+	return res, nil
+}
+
+func extractOutput(o *domain.StepOutput, res *domain.RunResult) (string, error) {
+	if o.Header != "" {
+		values := res.Header.Values(o.Header)
+		if len(values) == 0 {
+			return "", fmt.Errorf("output %s: header %s is missing", o.Name, o.Header)
+		}
+		return values[0], nil
+	}
+	doc, err := jsonpath.Decode(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("output %s: body is not JSON: %w", o.Name, err)
+	}
+	path, err := jsonpath.Parse(o.JSON)
+	if err != nil {
+		return "", err
+	}
+	v, ok := path.Lookup(doc)
+	if !ok {
+		return "", fmt.Errorf("output %s: %s is missing", o.Name, o.JSON)
+	}
+	return jsonpath.String(v), nil
+}
@@ -0,0 +1,221 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+type fakeRunner struct {
+	runs      []string
+	responses map[string]*domain.RunResult
+}
+
+func (r *fakeRunner) Run(ctx context.Context, a *domain.Action) (*domain.RunResult, error) {
+	var key string
+	switch a.Type {
+	case domain.ActionTypeHTTP:
+		key = a.Request.URI
+		for _, h := range a.Request.Headers {
+			key += " " + h.Name + ": " + h.Value
+		}
+	case domain.ActionTypeExec:
+		key = a.Command.Path
+		for _, arg := range a.Command.Args {
+			key += " " + arg
+		}
+	}
+	r.runs = append(r.runs, key)
+	res := r.responses[key]
+	if res == nil {
+		return &domain.RunResult{Code: 500}, errors.New("unexpected")
+	}
+	return res, nil
+}
+
+func newTestWorkflow(steps ...*domain.WorkflowStep) *domain.Action {
+	return &domain.Action{
+		Type:     domain.ActionTypeWorkflow,
+		Workflow: &domain.Workflow{Steps: steps},
+	}
+}
+
+func TestWorkflowRunnerOutputs(t *testing.T) {
+	fake := &fakeRunner{responses: map[string]*domain.RunResult{
+		"https://auth/token": {
+			Code:   200,
+			Header: http.Header{"X-Request-Id": {"r-1"}},
+			Body:   []byte(`{"access_token":"t0k3n","expires":9007199254740993}`),
+		},
+		"https://api/sync?id=r-1 Authorization: Bearer t0k3n X-Request-Id: r-1": {
+			Code: 204,
+		},
+		"/bin/notify completed 9007199254740993": {},
+	}}
+	a := newTestWorkflow(
+		&domain.WorkflowStep{
+			Name: "token",
+			Action: &domain.Action{
+				Type:    domain.ActionTypeHTTP,
+				Request: &domain.HTTPRequest{URI: "https://auth/token"},
+			},
+			Outputs: []*domain.StepOutput{
+				{Name: "token", JSON: "$.access_token"},
+				{Name: "expires", JSON: "$.expires"},
+				{Name: "requestId", Header: "X-Request-Id"},
+			},
+		},
+		&domain.WorkflowStep{
+			Name: "sync",
+			If:   ".token",
+			Action: &domain.Action{
+				Type: domain.ActionTypeHTTP,
+				Request: &domain.HTTPRequest{
+					URI: "https://api/sync?id={{.requestId}}",
+					Headers: []*domain.NameValuePair{
+						{Name: "Authorization", Value: "Bearer {{.token}}"},
+						{Name: "X-Request-Id", Value: "{{.requestId}}"},
+					},
+				},
+			},
+		},
+		&domain.WorkflowStep{
+			Name: "cleanup",
+			If:   `eq .token ""`,
+			Action: &domain.Action{
+				Type:    domain.ActionTypeHTTP,
+				Request: &domain.HTTPRequest{URI: "https://api/cleanup"},
+			},
+		},
+		&domain.WorkflowStep{
+			Name: "notify",
+			Action: &domain.Action{
+				Type: domain.ActionTypeExec,
+				Command: &domain.ExecCommand{
+					Path: "/bin/notify",
+					Args: []string{"{{.Status}}", "{{.expires}}"},
+				},
+			},
+		},
+	)
+	r := NewWorkflowRunner(map[string]domain.Runner{
+		domain.ActionTypeHTTP: fake,
+		domain.ActionTypeExec: fake,
+	})
+
+	res, err := r.Run(context.Background(), a)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := []string{
+		"https://auth/token",
+		"https://api/sync?id=r-1 Authorization: Bearer t0k3n X-Request-Id: r-1",
+		"/bin/notify completed 9007199254740993",
+	}
+	if !reflect.DeepEqual(fake.runs, expected) {
+		t.Errorf("runs: got: %q, expected: %q", fake.runs, expected)
+	}
+	msg := "token: completed\nsync: completed\ncleanup: skipped\nnotify: completed"
+	if res.Message != msg {
+		t.Errorf("message: got: %q, expected: %q", res.Message, msg)
+	}
+}
+
+func TestWorkflowRunnerAlways(t *testing.T) {
+	fake := &fakeRunner{responses: map[string]*domain.RunResult{
+		"https://auth/token": {Code: 200, Body: []byte(`{}`)},
+		"/bin/notify failed": {},
+	}}
+	a := newTestWorkflow(
+		&domain.WorkflowStep{
+			Name: "token",
+			Action: &domain.Action{
+				Type:    domain.ActionTypeHTTP,
+				Request: &domain.HTTPRequest{URI: "https://auth/token"},
+			},
+			Outputs: []*domain.StepOutput{{Name: "token", JSON: "$.access_token"}},
+		},
+		&domain.WorkflowStep{
+			Name: "sync",
+			Action: &domain.Action{
+				Type:    domain.ActionTypeHTTP,
+				Request: &domain.HTTPRequest{URI: "https://api/sync"},
+			},
+		},
+		&domain.WorkflowStep{
+			Name:   "notify",
+			Always: true,
+			Action: &domain.Action{
+				Type: domain.ActionTypeExec,
+				Command: &domain.ExecCommand{
+					Path: "/bin/notify",
+					Args: []string{"{{.Status}}"},
+				},
+			},
+		},
+	)
+	r := NewWorkflowRunner(map[string]domain.Runner{
+		domain.ActionTypeHTTP: fake,
+		domain.ActionTypeExec: fake,
+	})
+
+	res, err := r.Run(context.Background(), a)
+
+	if err == nil || err.Error() != "step token: output token: $.access_token is missing" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := []string{"https://auth/token", "/bin/notify failed"}
+	if !reflect.DeepEqual(fake.runs, expected) {
+		t.Errorf("runs: got: %q, expected: %q", fake.runs, expected)
+	}
+	msg := "token: failed\nsync: skipped\nnotify: completed"
+	if res.Message != msg {
+		t.Errorf("message: got: %q, expected: %q", res.Message, msg)
+	}
+	if res.Code != 200 {
+		t.Errorf("code: got: %d, expected: 200", res.Code)
+	}
+}
+
+func TestWorkflowRunnerInvalidURI(t *testing.T) {
+	fake := &fakeRunner{responses: map[string]*domain.RunResult{
+		"https://auth/token": {Code: 200, Body: []byte(`{"next":"file:///etc"}`)},
+	}}
+	a := newTestWorkflow(
+		&domain.WorkflowStep{
+			Name: "token",
+			Action: &domain.Action{
+				Type:    domain.ActionTypeHTTP,
+				Request: &domain.HTTPRequest{URI: "https://auth/token"},
+			},
+			Outputs: []*domain.StepOutput{{Name: "next", JSON: "$.next"}},
+		},
+		&domain.WorkflowStep{
+			Name: "next",
+			Action: &domain.Action{
+				Type:    domain.ActionTypeHTTP,
+				Request: &domain.HTTPRequest{URI: "{{.next}}"},
+			},
+		},
+	)
+	r := NewWorkflowRunner(map[string]domain.Runner{domain.ActionTypeHTTP: fake})
+
+	res, err := r.Run(context.Background(), a)
+
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	expected := []string{"https://auth/token"}
+	if !reflect.DeepEqual(fake.runs, expected) {
+		t.Errorf("runs: got: %q, expected: %q", fake.runs, expected)
+	}
+	msg := "token: completed\nnext: failed"
+	if res.Message != msg {
+		t.Errorf("message: got: %q, expected: %q", res.Message, msg)
+	}
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// MaxCalendars limits a number of calendars of a collection or a job.
+	MaxCalendars      = 10
+	MaxCalendarDates  = 400
+	MaxCalendarRanges = 100
+
+	calendarDateLayout = "2006-01-02"
+)
+
+// Excludes reports whether the time falls on a calendar date or within
+// a calendar time range.
+func (c *Calendar) Excludes(t time.Time) bool {
+	if len(c.Dates) > 0 {
+		loc, err := LoadLocation(c.Timezone)
+		if err != nil {
+			// validated before, fallback to UTC
+			loc = time.UTC
+		}
+		if slices.Contains(c.Dates, t.In(loc).Format(calendarDateLayout)) {
+			return true
+		}
+	}
+	for _, r := range c.Ranges {
+		if !t.Before(r.From) && t.Before(r.To) {
+			return true
+		}
+	}
+	return false
+}
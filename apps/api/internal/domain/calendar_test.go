@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendarExcludes(t *testing.T) {
+	from, _ := time.Parse(time.RFC3339, "2026-12-31T18:00:00Z")
+	to, _ := time.Parse(time.RFC3339, "2027-01-01T06:00:00Z")
+	c := &Calendar{
+		Timezone: "Europe/Berlin",
+		Dates:    []string{"2026-12-25"},
+		Ranges:   []*TimeRange{{From: from, To: to}},
+	}
+	var testcases = []struct {
+		t        string
+		expected bool
+	}{
+		{"2026-12-25T09:00:00Z", true},
+		{"2026-12-24T23:30:00Z", true},
+		{"2026-12-25T23:30:00Z", false},
+		{"2026-12-31T18:00:00Z", true},
+		{"2027-01-01T06:00:00Z", false},
+		{"2026-12-28T09:00:00Z", false},
+	}
+	for _, tt := range testcases {
+		at, _ := time.Parse(time.RFC3339, tt.t)
+		if actual := c.Excludes(at); actual != tt.expected {
+			t.Errorf("%s: got: %t, expected: %t", tt.t, actual, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package domain
+
+const (
+	ConcurrencyForbid  = "forbid"
+	ConcurrencyAllow   = "allow"
+	ConcurrencyReplace = "replace"
+)
+
+// MaxRuns returns the number of parallel runs permitted.
+func (p *ConcurrencyPolicy) MaxRuns() int {
+	if p == nil || p.Policy != ConcurrencyAllow || p.Limit < 1 {
+		return 1
+	}
+	return p.Limit
+}
+
+// Replace reports whether a new run cancels the running one.
+func (p *ConcurrencyPolicy) Replace() bool {
+	return p != nil && p.Policy == ConcurrencyReplace
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "testing"
+
+func TestConcurrencyPolicy(t *testing.T) {
+	var testcases = []struct {
+		policy  *ConcurrencyPolicy
+		maxRuns int
+		replace bool
+	}{
+		{nil, 1, false},
+		{&ConcurrencyPolicy{Policy: ConcurrencyForbid, Limit: 5}, 1, false},
+		{&ConcurrencyPolicy{Policy: ConcurrencyAllow, Limit: 3}, 3, false},
+		{&ConcurrencyPolicy{Policy: ConcurrencyReplace}, 1, true},
+	}
+	for _, tt := range testcases {
+		if actual := tt.policy.MaxRuns(); actual != tt.maxRuns {
+			t.Errorf("MaxRuns() got: %d, expected: %d", actual, tt.maxRuns)
+		}
+		if actual := tt.policy.Replace(); actual != tt.replace {
+			t.Errorf("Replace() got: %t, expected: %t", actual, tt.replace)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// extPrefix denotes an extended cron spec with optional seconds field,
+// last day of month (L), nearest weekday (W) and nth weekday of month (#),
+// e.g. @ext 0 18 * * 5L fires at 18:00 on the last Friday of the month.
+const extPrefix = "@ext "
+
+// maxExtDays limits the search of the next matching day, e.g. 29 Feb.
+const maxExtDays = 5 * 366
+
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	dowNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+)
+
+type extSchedule struct {
+	second, minute, hour, month uint64
+	dom                         domField
+	dow                         dowField
+	loc                         *time.Location
+}
+
+// domField matches days of month, the last one, the last weekday or
+// weekdays nearest to given days.
+type domField struct {
+	any         bool
+	days        uint64
+	last        bool
+	lastWeekday bool
+	nearest     []int
+}
+
+// dowField matches days of week, the last given weekday of month or
+// the nth one.
+type dowField struct {
+	any  bool
+	days uint64
+	last []int
+	nth  [][2]int
+}
+
+// parseExtended parses an extended cron spec to be evaluated in the
+// location: [second] minute hour day-of-month month day-of-week.
+func parseExtended(spec string, loc *time.Location) (*extSchedule, error) {
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("expected 5 or 6 fields, found %d: %s", len(fields), spec)
+	}
+	s := &extSchedule{loc: loc}
+	var err error
+	if s.second, err = parseBits(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if s.minute, err = parseBits(fields[1], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseBits(fields[2], 0, 23, nil); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseDom(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseBits(fields[4], 1, 12, monthNames); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseDow(fields[5]); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Next steps through the time in the location field by field, so a time
+// of day in a daylight saving time gap is skipped and one in an overlap
+// fires twice, the same way as the standard cron schedule.
+func (s *extSchedule) Next(t time.Time) time.Time {
+	orig := t.Location()
+	t = t.In(s.loc).Truncate(time.Second).Add(time.Second)
+	limit := t.AddDate(0, 0, maxExtDays)
+	for t.Before(limit) {
+		y, m, d := t.Date()
+		if !hasBit(s.month, int(m)) || !s.matchDay(time.Date(y, m, d, 0, 0, 0, 0, time.UTC)) {
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, s.loc)
+			for t.Day() == d {
+				// midnight in a gap is normalized to the day before
+				t = t.Add(time.Hour)
+			}
+			continue
+		}
+		// durations rather than dates keep the hour of an overlap
+		sec := time.Duration(t.Second()) * time.Second
+		if !hasBit(s.hour, t.Hour()) {
+			t = t.Add(time.Hour - time.Duration(t.Minute())*time.Minute - sec)
+			continue
+		}
+		if !hasBit(s.minute, t.Minute()) {
+			t = t.Add(time.Minute - sec)
+			continue
+		}
+		if !hasBit(s.second, t.Second()) {
+			t = t.Add(time.Second)
+			continue
+		}
+		return t.In(orig)
+	}
+	return time.Time{}
+}
+
+// matchDay follows cron semantics: unless either day field is a wildcard
+// the day matches any of them.
+func (s *extSchedule) matchDay(date time.Time) bool {
+	dom := s.dom.match(date)
+	dow := s.dow.match(date)
+	if s.dom.any || s.dow.any {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func (f *domField) match(date time.Time) bool {
+	if f.any {
+		return true
+	}
+	d := date.Day()
+	if hasBit(f.days, d) {
+		return true
+	}
+	last := lastDay(date)
+	if f.last && d == last {
+		return true
+	}
+	if f.lastWeekday && d == nearestWeekday(date, last) {
+		return true
+	}
+	for _, n := range f.nearest {
+		if d == nearestWeekday(date, n) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *dowField) match(date time.Time) bool {
+	if f.any {
+		return true
+	}
+	wd := int(date.Weekday())
+	if hasBit(f.days, wd) {
+		return true
+	}
+	for _, n := range f.last {
+		if wd == n && date.Day()+7 > lastDay(date) {
+			return true
+		}
+	}
+	for _, n := range f.nth {
+		if wd == n[0] && (date.Day()-1)/7+1 == n[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func lastDay(date time.Time) int {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nearestWeekday returns the weekday nearest to the day of the date month
+// without crossing the month boundaries.
+func nearestWeekday(date time.Time, day int) int {
+	last := lastDay(date)
+	if day > last {
+		day = last
+	}
+	wd := time.Date(date.Year(), date.Month(), day, 0, 0, 0, 0, time.UTC).Weekday()
+	switch {
+	case wd == time.Saturday && day == 1:
+		return day + 2
+	case wd == time.Saturday:
+		return day - 1
+	case wd == time.Sunday && day == last:
+		return day - 2
+	case wd == time.Sunday:
+		return day + 1
+	}
+	return day
+}
+
+func parseDom(field string) (domField, error) {
+	f := domField{any: field == "*" || field == "?"}
+	if f.any {
+		return f, nil
+	}
+	for _, item := range strings.Split(field, ",") {
+		switch {
+		case item == "L":
+			f.last = true
+		case item == "LW":
+			f.lastWeekday = true
+		case strings.HasSuffix(item, "W"):
+			n, err := parseNumber(item[:len(item)-1], 1, 31, nil)
+			if err != nil {
+				return f, err
+			}
+			f.nearest = append(f.nearest, n)
+		default:
+			bits, err := parseBits(item, 1, 31, nil)
+			if err != nil {
+				return f, err
+			}
+			f.days |= bits
+		}
+	}
+	return f, nil
+}
+
+func parseDow(field string) (dowField, error) {
+	f := dowField{any: field == "*" || field == "?"}
+	if f.any {
+		return f, nil
+	}
+	for _, item := range strings.Split(field, ",") {
+		switch {
+		case len(item) > 1 && strings.HasSuffix(item, "L"):
+			n, err := parseWeekday(item[:len(item)-1])
+			if err != nil {
+				return f, err
+			}
+			f.last = append(f.last, n)
+		case strings.Contains(item, "#"):
+			day, nth, _ := strings.Cut(item, "#")
+			n, err := parseWeekday(day)
+			if err != nil {
+				return f, err
+			}
+			k, err := parseNumber(nth, 1, 5, nil)
+			if err != nil {
+				return f, err
+			}
+			f.nth = append(f.nth, [2]int{n, k})
+		default:
+			bits, err := parseBits(item, 0, 7, dowNames)
+			if err != nil {
+				return f, err
+			}
+			// both 0 and 7 stand for Sunday
+			if hasBit(bits, 7) {
+				bits |= 1
+			}
+			f.days |= bits
+		}
+	}
+	return f, nil
+}
+
+func parseWeekday(s string) (int, error) {
+	n, err := parseNumber(s, 0, 7, dowNames)
+	return n % 7, err
+}
+
+// parseBits parses a comma separated list of *, a, a-b with optional
+// step into a bit set.
+func parseBits(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		expr, step, hasStep := strings.Cut(item, "/")
+		var lo, hi int
+		var err error
+		switch {
+		case expr == "*" || expr == "?":
+			lo, hi = min, max
+		case strings.Contains(expr, "-"):
+			a, b, _ := strings.Cut(expr, "-")
+			if lo, err = parseNumber(a, min, max, names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseNumber(b, min, max, names); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("beginning of range (%d) beyond end of range (%d): %s", lo, hi, item)
+			}
+		default:
+			if lo, err = parseNumber(expr, min, max, names); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if hasStep {
+				hi = max
+			}
+		}
+		n := 1
+		if hasStep {
+			if n, err = strconv.Atoi(step); err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step: %s", item)
+			}
+		}
+		for i := lo; i <= hi; i += n {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
+func parseNumber(s string, min, max int, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToUpper(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse int from %s", s)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("value (%d) out of range [%d, %d]: %s", n, min, max, s)
+	}
+	return n, nil
+}
+
+func hasBit(bits uint64, i int) bool {
+	return bits&(1<<uint(i)) != 0
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseExtendedSchedule(t *testing.T) {
+	var testcases = []struct {
+		spec     string
+		tz       string
+		after    string
+		expected string
+	}{
+		{"@ext 0 9 * * *", "", "2024-10-01T09:00:00Z", "2024-10-02T09:00:00Z"},
+		{"@ext 30 0 9 * * *", "", "2024-10-01T09:00:00Z", "2024-10-01T09:00:30Z"},
+		{"@ext */15 * * * * *", "", "2024-10-01T09:00:07Z", "2024-10-01T09:00:15Z"},
+		{"@ext 0 0 1 JAN *", "", "2024-10-01T00:00:00Z", "2025-01-01T00:00:00Z"},
+		{"@ext 0 9 L * *", "", "2024-02-01T00:00:00Z", "2024-02-29T09:00:00Z"},
+		{"@ext 0 9 15W * *", "", "2024-06-01T00:00:00Z", "2024-06-14T09:00:00Z"},
+		{"@ext 0 9 1W * *", "", "2024-06-01T00:00:00Z", "2024-06-03T09:00:00Z"},
+		{"@ext 0 9 LW * *", "", "2024-08-01T00:00:00Z", "2024-08-30T09:00:00Z"},
+		{"@ext 0 9 LW * *", "", "2024-03-01T00:00:00Z", "2024-03-29T09:00:00Z"},
+		{"@ext 0 18 * * 5L", "", "2024-10-01T00:00:00Z", "2024-10-25T18:00:00Z"},
+		{"@ext 0 9 * * MON#2", "", "2024-10-01T00:00:00Z", "2024-10-14T09:00:00Z"},
+		{"@ext 0 9 * * 7#1", "", "2024-10-01T00:00:00Z", "2024-10-06T09:00:00Z"},
+		{"@ext 0 9 * * 1#1", "Europe/Berlin", "2024-10-01T00:00:00Z", "2024-10-07T07:00:00Z"},
+		{"@ext 0 9 13 * 5", "", "2024-10-01T00:00:00Z", "2024-10-04T09:00:00Z"},
+		{"@ext 0 9 ? * MON-FRI", "", "2024-10-04T10:00:00Z", "2024-10-07T09:00:00Z"},
+		{"@ext 0 0 30 2 *", "", "2024-10-01T00:00:00Z", "0001-01-01T00:00:00Z"},
+	}
+	for _, tt := range testcases {
+		sched, err := ParseSchedule(tt.spec, tt.tz)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.spec, err)
+		}
+		after, _ := time.Parse(time.RFC3339, tt.after)
+		actual := sched.Next(after).UTC().Format(time.RFC3339)
+		if actual != tt.expected {
+			t.Errorf("%s in %q: got: %s, expected: %s", tt.spec, tt.tz, actual, tt.expected)
+		}
+	}
+}
+
+func TestExtendedScheduleDaylightSaving(t *testing.T) {
+	var testcases = []struct {
+		after    string
+		spec     string
+		expected string
+	}{
+		// 2am EST (-5) -> 3am EDT (-4)
+		{"2012-03-11T00:00:00-05:00", "0 30 2 11 MAR *", "2013-03-11T02:30:00-04:00"},
+		{"2012-03-11T00:00:00-05:00", "0 0 * * * *", "2012-03-11T01:00:00-05:00"},
+		{"2012-03-11T01:00:00-05:00", "0 0 * * * *", "2012-03-11T03:00:00-04:00"},
+		{"2012-03-11T03:00:00-04:00", "0 0 * * * *", "2012-03-11T04:00:00-04:00"},
+		{"2012-03-11T00:00:00-05:00", "0 0 1 * * *", "2012-03-11T01:00:00-05:00"},
+		{"2012-03-11T01:00:00-05:00", "0 0 1 * * *", "2012-03-12T01:00:00-04:00"},
+		{"2012-03-11T00:00:00-05:00", "0 0 2 * * *", "2012-03-12T02:00:00-04:00"},
+		{"2012-03-11T01:55:00-05:00", "*/5 * * * *", "2012-03-11T03:00:00-04:00"},
+		// 2am EDT (-4) -> 1am EST (-5)
+		{"2012-11-04T00:00:00-04:00", "0 30 2 4 NOV *", "2012-11-04T02:30:00-05:00"},
+		{"2012-11-04T01:45:00-04:00", "0 30 1 4 NOV *", "2012-11-04T01:30:00-05:00"},
+		{"2012-11-04T00:00:00-04:00", "0 0 * * * *", "2012-11-04T01:00:00-04:00"},
+		{"2012-11-04T01:00:00-04:00", "0 0 * * * *", "2012-11-04T01:00:00-05:00"},
+		{"2012-11-04T01:00:00-05:00", "0 0 * * * *", "2012-11-04T02:00:00-05:00"},
+		{"2012-11-04T00:00:00-04:00", "0 0 1 * * *", "2012-11-04T01:00:00-04:00"},
+		{"2012-11-04T01:00:00-04:00", "0 0 1 * * *", "2012-11-04T01:00:00-05:00"},
+		{"2012-11-04T01:00:00-05:00", "0 0 1 * * *", "2012-11-05T01:00:00-05:00"},
+		{"2012-11-04T00:00:00-04:00", "0 0 2 * * *", "2012-11-04T02:00:00-05:00"},
+		{"2012-11-04T02:00:00-05:00", "0 0 2 * * *", "2012-11-05T02:00:00-05:00"},
+		{"2012-11-04T00:00:00-04:00", "0 0 3 * * *", "2012-11-04T03:00:00-05:00"},
+		{"2026-11-01T01:10:00-05:00", "*/5 * * * *", "2026-11-01T01:15:00-05:00"},
+		{"2026-11-01T01:55:00-04:00", "*/5 * * * *", "2026-11-01T01:00:00-05:00"},
+	}
+	for _, tt := range testcases {
+		sched, err := ParseSchedule("@ext "+tt.spec, "America/New_York")
+		if err != nil {
+			t.Fatalf("%s: %s", tt.spec, err)
+		}
+		after, _ := time.Parse(time.RFC3339, tt.after)
+		expected, _ := time.Parse(time.RFC3339, tt.expected)
+		if actual := sched.Next(after); !actual.Equal(expected) {
+			t.Errorf("%s after %s: got: %s, expected: %s", tt.spec, tt.after, actual, expected)
+		}
+	}
+	// midnight is not a valid time
+	sched, _ := ParseSchedule("@ext 0 0 9 10 * *", "America/Sao_Paulo")
+	after, _ := time.Parse(time.RFC3339, "2018-10-17T05:00:00-04:00")
+	if actual := sched.Next(after).UTC().Format(time.RFC3339); actual != "2018-11-10T11:00:00Z" {
+		t.Errorf("got: %s, expected: 2018-11-10T11:00:00Z", actual)
+	}
+}
+
+func TestParseExtendedScheduleFails(t *testing.T) {
+	for _, spec := range []string{
+		"@ext 0 9 * *", "@ext 60 0 9 * * *", "@ext 0 9 32W * *",
+		"@ext 0 9 * * 8L", "@ext 0 9 * * 1#0", "@ext */0 * * * *",
+		"@ext 0 9 5-1 * *", "@ext 0 9 * * L",
+	} {
+		if _, err := ParseSchedule(spec, ""); err == nil {
+			t.Errorf("%s: expected error", spec)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package domain
+
+// Elector elects a single leader among all instances. A leader keeps
+// the leadership until it stops or loses the connection.
+type Elector interface {
+	Start()
+	Stop()
+	// IsLeader reports whether this instance is the leader.
+	IsLeader() bool
+	// Leader returns a name of the current leader, empty if none.
+	Leader() (string, error)
+}
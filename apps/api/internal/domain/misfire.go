@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	MisfireSkip = "skip"
+	MisfireOnce = "once"
+	MisfireAll  = "all"
+
+	DefaultMisfireLimit = 10
+
+	// MaxMisfireAge bounds how far back missed ticks are caught up, it is
+	// well within the retention of finished runs, 7 days, so a tick run
+	// already is not enqueued again.
+	MaxMisfireAge = 24 * time.Hour
+)
+
+// Missed returns ticks of the schedule missed since a time, but no older
+// than MaxMisfireAge, until now to catch up: the latest one for once
+// policy or the earliest ones up to the limit for all policy.
+func (p *MisfirePolicy) Missed(sched cron.Schedule, since, now time.Time) []time.Time {
+	if p == nil || p.Policy == "" || p.Policy == MisfireSkip {
+		return nil
+	}
+	if oldest := now.Add(-MaxMisfireAge); since.Before(oldest) {
+		since = oldest
+	}
+	limit := p.Limit
+	if limit == 0 {
+		limit = DefaultMisfireLimit
+	}
+	var ticks []time.Time
+	for t := sched.Next(since); !t.IsZero() && !t.After(now); t = sched.Next(t) {
+		if p.Policy == MisfireOnce {
+			ticks = []time.Time{t}
+			continue
+		}
+		ticks = append(ticks, t)
+		if len(ticks) == limit {
+			break
+		}
+	}
+	return ticks
+}
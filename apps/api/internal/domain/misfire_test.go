@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMisfirePolicyMissed(t *testing.T) {
+	since := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := since.Add(5*time.Hour + 30*time.Minute)
+	var testcases = []struct {
+		policy   *MisfirePolicy
+		spec     string
+		expected int
+	}{
+		{nil, "0 * * * *", 0},
+		{&MisfirePolicy{Policy: MisfireSkip}, "0 * * * *", 0},
+		{&MisfirePolicy{Policy: MisfireOnce}, "0 * * * *", 1},
+		{&MisfirePolicy{Policy: MisfireOnce}, "0 0 * * *", 0},
+		{&MisfirePolicy{Policy: MisfireAll}, "0 * * * *", 5},
+		{&MisfirePolicy{Policy: MisfireAll, Limit: 3}, "0 * * * *", 3},
+		{&MisfirePolicy{Policy: MisfireAll}, "@every 1m", DefaultMisfireLimit},
+		{&MisfirePolicy{Policy: MisfireAll}, "@at 2024-01-01T12:00:00Z", 1},
+		{&MisfirePolicy{Policy: MisfireAll}, "@at 2024-01-01T18:00:00Z", 0},
+	}
+	for _, tt := range testcases {
+		sched, err := ParseSchedule(tt.spec, "")
+		if err != nil {
+			t.Fatalf("%s: %s", tt.spec, err)
+		}
+		actual := len(tt.policy.Missed(sched, since, now))
+		if actual != tt.expected {
+			t.Errorf("%v %s: got: %d, expected: %d", tt.policy, tt.spec, actual, tt.expected)
+		}
+	}
+}
+
+func TestMisfirePolicyMissedTicks(t *testing.T) {
+	since := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := since.Add(5*time.Hour + 30*time.Minute)
+	sched, _ := ParseSchedule("0 * * * *", "")
+	var testcases = []struct {
+		policy   *MisfirePolicy
+		expected []time.Time
+	}{
+		{
+			&MisfirePolicy{Policy: MisfireOnce},
+			[]time.Time{since.Add(5 * time.Hour)},
+		},
+		{
+			&MisfirePolicy{Policy: MisfireAll, Limit: 2},
+			[]time.Time{since.Add(time.Hour), since.Add(2 * time.Hour)},
+		},
+	}
+	for _, tt := range testcases {
+		actual := tt.policy.Missed(sched, since, now)
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("%v: got: %v, expected: %v", tt.policy, actual, tt.expected)
+		}
+	}
+}
+
+func TestMisfirePolicyMissedMaxAge(t *testing.T) {
+	now := time.Date(2024, 1, 10, 10, 30, 0, 0, time.UTC)
+	since := now.Add(-7 * 24 * time.Hour)
+	sched, _ := ParseSchedule("0 * * * *", "")
+	p := &MisfirePolicy{Policy: MisfireAll, Limit: 2}
+
+	actual := p.Missed(sched, since, now)
+
+	expected := []time.Time{
+		now.Add(-MaxMisfireAge + 30*time.Minute),
+		now.Add(-MaxMisfireAge + 90*time.Minute),
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got: %v, expected: %v", actual, expected)
+	}
+}
@@ -14,9 +14,17 @@ var (
 	Version = "0.0.0-dev"
 )
 
+const (
+	ActionTypeHTTP     = "HTTP"
+	ActionTypeExec     = "EXEC"
+	ActionTypeSQL      = "SQL"
+	ActionTypeWorkflow = "WORKFLOW"
+)
+
 var (
-	ErrConflict = errors.New("conflict")
-	ErrNotFound = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrNotFound     = errors.New("not found")
+	ErrDuplicateRun = errors.New("duplicate run")
 )
 
 type (
@@ -32,11 +40,42 @@ type (
 		State CollectionState `json:"state"`
 	}
 
+	// Collection priority and max runs apply to runs of the collection
+	// jobs: runs of a higher priority are claimed first, a number of
+	// simultaneous runs is limited unless max runs is zero. The collection
+	// jobs are not fired until paused until time.
 	Collection struct {
 		CollectionItem
+		Updated     time.Time  `json:"updated"`
+		Timezone    string     `json:"timezone,omitempty"`
+		Priority    int        `json:"priority,omitempty"`
+		MaxRuns     int        `json:"maxRuns,omitempty"`
+		Jitter      Duration   `json:"jitter,omitempty"`
+		PausedUntil *time.Time `json:"pausedUntil,omitempty"`
+		Calendars   []string   `json:"calendars,omitempty"`
+	}
+
+	CalendarItem struct {
+		ID      string    `json:"id"`
+		Name    string    `json:"name"`
 		Updated time.Time `json:"updated"`
 	}
 
+	// Calendar excludes fires on dates, evaluated in the calendar timezone,
+	// and within time ranges, e.g. public holidays or change freezes.
+	Calendar struct {
+		CalendarItem
+		Timezone string       `json:"timezone,omitempty"`
+		Dates    []string     `json:"dates,omitempty"`
+		Ranges   []*TimeRange `json:"ranges,omitempty"`
+	}
+
+	// TimeRange is a time range since from prior to to.
+	TimeRange struct {
+		From time.Time `json:"from"`
+		To   time.Time `json:"to"`
+	}
+
 	VariableItem struct {
 		ID           string    `json:"id"`
 		Name         string    `json:"name"`
@@ -59,16 +98,61 @@ type (
 		ErrorRate    *float32       `json:"errorRate,omitempty"`
 	}
 
+	// JobDefinition fires on the union of the schedule and additional
+	// schedules evaluated in the job timezone, if not specified, in the
+	// collection one, otherwise in UTC. The same applies to the priority
+	// and jitter. The job is fired since not before time and prior to not
+	// after time only. Fires within calendars of the job and the
+	// collection are skipped. The job is also fired by triggers once
+	// upstream jobs finish, the schedule is optional then.
 	JobDefinition struct {
 		JobItem
-		Updated time.Time `json:"updated"`
-		Action  *Action   `json:"action"`
+		Updated     time.Time          `json:"updated"`
+		Schedules   []string           `json:"schedules,omitempty"`
+		Timezone    string             `json:"timezone,omitempty"`
+		Priority    *int               `json:"priority,omitempty"`
+		Jitter      Duration           `json:"jitter,omitempty"`
+		NotBefore   *time.Time         `json:"notBefore,omitempty"`
+		NotAfter    *time.Time         `json:"notAfter,omitempty"`
+		Calendars   []string           `json:"calendars,omitempty"`
+		Triggers    []*JobTrigger      `json:"triggers,omitempty"`
+		Misfire     *MisfirePolicy     `json:"misfire,omitempty"`
+		Concurrency *ConcurrencyPolicy `json:"concurrency,omitempty"`
+		Action      *Action            `json:"action"`
+	}
+
+	// ConcurrencyPolicy controls overlapping runs of a job: forbid skips
+	// a run, allow permits up to limit parallel runs and replace cancels
+	// the running one.
+	ConcurrencyPolicy struct {
+		Policy string `json:"policy"`
+		Limit  int    `json:"limit,omitempty"`
+	}
+
+	// JobTrigger fires the job once the upstream job finishes with the
+	// outcome: completed, failed or any, optionally after a delay.
+	JobTrigger struct {
+		JobID   string   `json:"jobId"`
+		Outcome string   `json:"outcome"`
+		Delay   Duration `json:"delay,omitempty"`
+	}
+
+	// MisfirePolicy controls ticks missed while no instance was up: skip
+	// them, run once or run each of them up to a limit.
+	MisfirePolicy struct {
+		Policy string `json:"policy"`
+		Limit  int    `json:"limit,omitempty"`
 	}
 
 	Action struct {
-		Type        string       `json:"type"`
-		Request     *HTTPRequest `json:"request"`
-		RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+		Type        string          `json:"type"`
+		Request     *HTTPRequest    `json:"request,omitempty"`
+		Command     *ExecCommand    `json:"command,omitempty"`
+		Statement   *SQLStatement   `json:"statement,omitempty"`
+		Workflow    *Workflow       `json:"workflow,omitempty"`
+		Assertions  *HTTPAssertions `json:"assertions,omitempty"`
+		Snapshot    *SnapshotPolicy `json:"snapshot,omitempty"`
+		RetryPolicy *RetryPolicy    `json:"retryPolicy,omitempty"`
 	}
 
 	HTTPRequest struct {
@@ -78,6 +162,77 @@ type (
 		Body    string           `json:"body,omitempty"`
 	}
 
+	// HTTPAssertions are checks of an HTTP response, all of them must pass
+	// for a run to be completed. If status codes are not specified, any 2xx
+	// status code is expected.
+	HTTPAssertions struct {
+		StatusCodes []int            `json:"statusCodes,omitempty"`
+		Headers     []*NameValuePair `json:"headers,omitempty"`
+		Body        string           `json:"body,omitempty"`
+		BodyPattern string           `json:"bodyPattern,omitempty"`
+		JSON        []*NameValuePair `json:"json,omitempty"`
+	}
+
+	// SnapshotPolicy controls what part of an HTTP response is kept in the
+	// job history. Matches of redact patterns in the captured header values
+	// and body are masked.
+	SnapshotPolicy struct {
+		Headers   []string `json:"headers,omitempty"`
+		BodyLimit int      `json:"bodyLimit,omitempty"`
+		Redact    []string `json:"redact,omitempty"`
+	}
+
+	HTTPResponse struct {
+		StatusCode int              `json:"statusCode"`
+		Headers    []*NameValuePair `json:"headers,omitempty"`
+		Body       string           `json:"body,omitempty"`
+		Size       int              `json:"size"`
+	}
+
+	ExecCommand struct {
+		Path  string           `json:"path"`
+		Args  []string         `json:"args,omitempty"`
+		Dir   string           `json:"dir,omitempty"`
+		Env   []*NameValuePair `json:"env,omitempty"`
+		Stdin string           `json:"stdin,omitempty"`
+	}
+
+	SQLStatement struct {
+		DSN     string `json:"dsn"`
+		Query   string `json:"query"`
+		MaxRows int    `json:"maxRows,omitempty"`
+	}
+
+	// Workflow runs the steps in order, a step runs only if the previous
+	// ones succeeded and its condition holds, unless it runs always, e.g.
+	// a cleanup. Values extracted from a step response are template
+	// variables of the later steps, a step action is rendered the same way
+	// as a job action. A step has no retry policy, the workflow one runs
+	// all the steps again, including the always ones.
+	Workflow struct {
+		Steps []*WorkflowStep `json:"steps"`
+
+		variables map[string]string
+	}
+
+	// WorkflowStep condition is a template pipeline, e.g. `.token` or
+	// `eq .Status "failed"`, the step is skipped unless it holds.
+	WorkflowStep struct {
+		Name    string        `json:"name"`
+		If      string        `json:"if,omitempty"`
+		Always  bool          `json:"always,omitempty"`
+		Action  *Action       `json:"action"`
+		Outputs []*StepOutput `json:"outputs,omitempty"`
+	}
+
+	// StepOutput is a value of JSONPath in a step response body or of a
+	// response header.
+	StepOutput struct {
+		Name   string `json:"name"`
+		JSON   string `json:"json,omitempty"`
+		Header string `json:"header,omitempty"`
+	}
+
 	NameValuePair struct {
 		Name  string `json:"name"`
 		Value string `json:"value"`
@@ -87,6 +242,15 @@ type (
 		RetryCount    int      `json:"retryCount"`
 		RetryInterval Duration `json:"retryInterval"`
 		Deadline      Duration `json:"deadline"`
+		Backoff       string   `json:"backoff,omitempty"`
+		Multiplier    float64  `json:"multiplier,omitempty"`
+		MaxInterval   Duration `json:"maxInterval,omitempty"`
+		Jitter        string   `json:"jitter,omitempty"`
+		// RetryableCodes are status codes (e.g. 429) or classes (e.g. 5xx)
+		// and RetryableErrors are kinds of errors (e.g. timeout) to retry.
+		// Either if not specified defaults to any except client errors.
+		RetryableCodes  []string `json:"retryableCodes,omitempty"`
+		RetryableErrors []string `json:"retryableErrors,omitempty"`
 	}
 
 	JobStatus struct {
@@ -96,16 +260,59 @@ type (
 		ErrorCount int        `json:"errorCount"`
 		LastRun    *time.Time `json:"lastRun,omitempty"`
 		NextRun    *time.Time `json:"nextRun,omitempty"`
+		Pending    int        `json:"pending,omitempty"`
+		Generation int        `json:"-"`
+	}
+
+	// UpcomingQuery selects planned runs of enabled jobs in enabled
+	// collections scheduled after from up to to.
+	UpcomingQuery struct {
+		CollectionID string
+		From         time.Time
+		To           time.Time
+		Mode         string
+	}
+
+	PlannedRun struct {
+		JobID        string    `json:"jobId"`
+		CollectionID string    `json:"collectionId"`
+		Scheduled    time.Time `json:"scheduled"`
+	}
+
+	// HistogramBucket is a number of planned runs within a minute.
+	HistogramBucket struct {
+		Minute time.Time `json:"minute"`
+		Count  int       `json:"count"`
+	}
+
+	// JobRun is a run of a job for a scheduled tick in the queue.
+	JobRun struct {
+		JobID     string
+		Scheduled time.Time
 	}
 
 	JobHistory struct {
+		ID         string           `json:"id"`
 		JobID      string           `json:"-"`
 		Action     string           `json:"action"`
+		Scheduled  *time.Time       `json:"scheduled,omitempty"`
 		Started    time.Time        `json:"started"`
 		Finished   time.Time        `json:"finished"`
 		Status     JobHistoryStatus `json:"status"`
 		RetryCount int              `json:"retryCount,omitempty"`
 		Message    *string          `json:"message,omitempty"`
+		Response   *HTTPResponse    `json:"response,omitempty"`
+		Attempts   []*JobAttempt    `json:"attempts,omitempty"`
+	}
+
+	// JobAttempt is a single try of a job run. Delay is the time waited
+	// after the previous attempt.
+	JobAttempt struct {
+		Started  time.Time `json:"started"`
+		Finished time.Time `json:"finished"`
+		Code     int       `json:"code,omitempty"`
+		Message  *string   `json:"message,omitempty"`
+		Delay    Duration  `json:"delay,omitempty"`
 	}
 
 	UpdateEvent struct {
@@ -122,6 +329,11 @@ var (
 		Deadline:      20 * Duration(time.Second),
 	}
 
+	DefaultSnapshotPolicy = &SnapshotPolicy{
+		Headers:   []string{"Content-Type"},
+		BodyLimit: 1024,
+	}
+
 	Connected    = &UpdateEvent{ObjectType: "connection", Operation: "connected"}
 	Disconnected = &UpdateEvent{ObjectType: "connection", Operation: "disconnected"}
 	Reconnected  = &UpdateEvent{ObjectType: "connection", Operation: "reconnected"}
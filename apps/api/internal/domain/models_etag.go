@@ -13,17 +13,21 @@ func (c *Variable) ETag() string {
 	return etag(c.Updated)
 }
 
+func (c *Calendar) ETag() string {
+	return etag(c.Updated)
+}
+
 func (j *JobDefinition) ETag() string {
 	return etag(j.Updated)
 }
 
 func (j *JobStatus) ETag() string {
-	if j.NextRun == nil {
-		return etag(j.Updated)
+	// pending runs change without the status update
+	t := j.Updated.UnixMicro() + int64(j.Pending)
+	if j.NextRun != nil {
+		t += j.NextRun.UnixMicro()
 	}
-
-	return "\"" + strconv.FormatInt(
-		j.Updated.UnixMicro()+j.NextRun.UnixMicro(), 36) + "\""
+	return "\"" + strconv.FormatInt(t, 36) + "\""
 }
 
 func etag(t time.Time) string {
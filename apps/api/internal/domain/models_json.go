@@ -27,11 +27,12 @@ const (
 const (
 	JobHistoryStatusCompleted JobHistoryStatus = iota + 1
 	JobHistoryStatusFailed
+	JobHistoryStatusSkipped
 )
 
 var (
 	errInvalidState  = errors.New("state must be either 'enabled' or 'disabled'")
-	errInvalidStatus = errors.New("status must be either 'completed' or 'failed'")
+	errInvalidStatus = errors.New("status must be one of 'completed', 'failed' or 'skipped'")
 
 	collectionStateToString = map[CollectionState]string{
 		CollectionStateEnabled:  "enabled",
@@ -63,11 +64,13 @@ var (
 	jobHistoryStatusToString = map[JobHistoryStatus]string{
 		JobHistoryStatusCompleted: "completed",
 		JobHistoryStatusFailed:    "failed",
+		JobHistoryStatusSkipped:   "skipped",
 	}
 
 	jobHistoryStatusToID = map[string]JobHistoryStatus{
 		"completed": JobHistoryStatusCompleted,
 		"failed":    JobHistoryStatusFailed,
+		"skipped":   JobHistoryStatusSkipped,
 	}
 )
 
@@ -143,6 +143,7 @@ func TestJobHistoryStatusString(t *testing.T) {
 	}{
 		{JobHistoryStatusCompleted, `completed`},
 		{JobHistoryStatusFailed, `failed`},
+		{JobHistoryStatusSkipped, `skipped`},
 	}
 	for _, tt := range testcases {
 		s := tt.sample.String()
@@ -159,6 +160,7 @@ func TestJobHistoryStatusMarshalJSON(t *testing.T) {
 	}{
 		{JobHistoryStatusCompleted, `"completed"`},
 		{JobHistoryStatusFailed, `"failed"`},
+		{JobHistoryStatusSkipped, `"skipped"`},
 	}
 	for _, tt := range testcases {
 		b, _ := tt.sample.MarshalJSON()
@@ -177,9 +179,10 @@ func TestJobHistoryStatusUnmarshalJSON(t *testing.T) {
 	}{
 		{`10`, JobHistoryStatus(0), "json: cannot unmarshal number into Go value of type string"},
 		{`10s`, JobHistoryStatus(0), "invalid character 's' after top-level value"},
-		{`"X"`, JobHistoryStatus(0), "status must be either 'completed' or 'failed'"},
+		{`"X"`, JobHistoryStatus(0), "status must be one of 'completed', 'failed' or 'skipped'"},
 		{`"completed"`, JobHistoryStatusCompleted, ""},
 		{`"failed"`, JobHistoryStatusFailed, ""},
+		{`"skipped"`, JobHistoryStatusSkipped, ""},
 	}
 	for _, tt := range testcases {
 		var s JobHistoryStatus
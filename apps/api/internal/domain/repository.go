@@ -21,19 +21,45 @@ type Repository interface {
 	UpdateVariable(v *Variable) error
 	DeleteVariable(id string) error
 
+	ListCalendars() ([]*CalendarItem, error)
+	CreateCalendar(c *Calendar) error
+	RetrieveCalendar(id string) (*Calendar, error)
+	UpdateCalendar(c *Calendar) error
+	DeleteCalendar(id string) error
+
 	ListJobs(collectionID string, fields []string) ([]*JobItem, error)
 	CreateJob(j *JobDefinition) error
 	RetrieveJob(id string) (*JobDefinition, error)
 	UpdateJob(j *JobDefinition) error
 	DeleteJob(id string) error
+	// ListEnabledJobs returns definitions of enabled jobs of enabled
+	// collections, of the collection if specified, with the collection
+	// settings inherited.
+	ListEnabledJobs(collectionID string) ([]*JobDefinition, error)
+	// ListJobTriggers returns triggers of jobs keyed by the job ID.
+	ListJobTriggers() (map[string][]*JobTrigger, error)
+	// ListDependentJobs returns triggers of enabled jobs of enabled
+	// collections fired by the upstream job keyed by the job ID.
+	ListDependentJobs(upstreamID string) (map[string][]*JobTrigger, error)
 
 	RetrieveJobStatus(id string) (*JobStatus, error)
 	ListLeftOverJobs() ([]string, error)
 	ResetJobStatus(id string) error
 
 	ListJobHistory(id string) ([]*JobHistory, error)
+	RetrieveJobHistory(jobID, id string) (*JobHistory, error)
 	DeleteJobHistory(id string, before time.Time) error
 
-	AcquireJob(id string, deadline time.Duration) error
+	// AcquireJob marks the job running according to the concurrency policy
+	// and returns the job run generation, ErrConflict if not permitted.
+	AcquireJob(id string, deadline time.Duration, p *ConcurrencyPolicy) (int, error)
 	AddJobHistory(*JobHistory) error
+
+	// EnqueueJobRun adds a run of the job for the scheduled tick to the
+	// queue, ErrDuplicateRun if the tick is already enqueued.
+	EnqueueJobRun(id string, scheduled time.Time) error
+	// ClaimJobRun takes the highest priority, then earliest, queued run,
+	// ErrNotFound if none.
+	ClaimJobRun() (*JobRun, error)
+	FinishJobRun(r *JobRun) error
 }
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	BackoffFixed       = "fixed"
+	BackoffLinear      = "linear"
+	BackoffExponential = "exponential"
+
+	JitterNone  = "none"
+	JitterFull  = "full"
+	JitterEqual = "equal"
+
+	DefaultMultiplier = 2
+
+	ErrorKindTimeout    = "timeout"
+	ErrorKindConnection = "connection"
+	ErrorKindDNS        = "dns"
+)
+
+// unrecoverableCodes are client error responses that are not going to
+// succeed on retry.
+// https://developer.mozilla.org/en-US/docs/Web/HTTP/Status#Client_error_responses
+var unrecoverableCodes = map[int]bool{
+	400: true, 401: true, 403: true, 404: true, 422: true,
+}
+
+// Interval returns a delay before the retry n, starting at 1, according
+// to the backoff strategy, limited by the max interval and randomized by
+// the jitter.
+func (p *RetryPolicy) Interval(n int) time.Duration {
+	d := p.backoff(n)
+	if d <= 0 {
+		return 0
+	}
+	switch p.Jitter {
+	case JitterFull:
+		//nolint:gosec
+		d = time.Duration(rand.Int64N(int64(d) + 1))
+	case JitterEqual:
+		//nolint:gosec
+		d = d/2 + time.Duration(rand.Int64N(int64(d/2)+1))
+	}
+	return d
+}
+
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	interval := float64(p.RetryInterval)
+	switch p.Backoff {
+	case BackoffLinear:
+		interval *= float64(n)
+	case BackoffExponential:
+		m := p.Multiplier
+		if m == 0 {
+			m = DefaultMultiplier
+		}
+		interval *= math.Pow(m, float64(n-1))
+	}
+	if p.MaxInterval > 0 && interval > float64(p.MaxInterval) {
+		return time.Duration(p.MaxInterval)
+	}
+	if interval >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(interval)
+}
+
+// Retryable reports whether a run failed with the error should be retried.
+func (p *RetryPolicy) Retryable(err error) bool {
+	var re *RunError
+	if errors.As(err, &re) {
+		if len(p.RetryableCodes) == 0 {
+			return !unrecoverableCodes[re.Code]
+		}
+		code := strconv.Itoa(re.Code)
+		for _, c := range p.RetryableCodes {
+			if c == code ||
+				len(c) == 3 && c[1:] == "xx" && len(code) == 3 && c[0] == code[0] {
+				return true
+			}
+		}
+		return false
+	}
+	if len(p.RetryableErrors) == 0 {
+		return true
+	}
+	return slices.Contains(p.RetryableErrors, errorKind(err))
+}
+
+func errorKind(err error) string {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return ErrorKindDNS
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return ErrorKindConnection
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorKindTimeout
+	}
+	return ""
+}
+
+// RetryAfter returns a delay requested by the Retry-After header of
+// 429 Too Many Requests or 503 Service Unavailable response.
+func RetryAfter(err error, now time.Time) (time.Duration, bool) {
+	var re *RunError
+	if !errors.As(err, &re) ||
+		re.Code != http.StatusTooManyRequests &&
+			re.Code != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	v := re.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return max(t.Sub(now), 0), true
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyInterval(t *testing.T) {
+	var testcases = []struct {
+		policy   RetryPolicy
+		expected []time.Duration
+	}{
+		{
+			RetryPolicy{RetryInterval: Duration(5 * time.Second)},
+			[]time.Duration{5 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			RetryPolicy{
+				RetryInterval: Duration(5 * time.Second),
+				Backoff:       BackoffLinear,
+			},
+			[]time.Duration{5 * time.Second, 10 * time.Second, 15 * time.Second},
+		},
+		{
+			RetryPolicy{
+				RetryInterval: Duration(time.Second),
+				Backoff:       BackoffExponential,
+				MaxInterval:   Duration(5 * time.Second),
+			},
+			[]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second},
+		},
+		{
+			RetryPolicy{
+				RetryInterval: Duration(time.Second),
+				Backoff:       BackoffExponential,
+				Multiplier:    1.5,
+			},
+			[]time.Duration{time.Second, 1500 * time.Millisecond, 2250 * time.Millisecond},
+		},
+	}
+	for _, tt := range testcases {
+		for i, expected := range tt.expected {
+			actual := tt.policy.Interval(i + 1)
+			if actual != expected {
+				t.Errorf("Interval(%d) got: %s, expected: %s", i+1, actual, expected)
+			}
+		}
+	}
+}
+
+func TestRetryPolicyIntervalJitter(t *testing.T) {
+	var testcases = []struct {
+		jitter string
+		min    time.Duration
+	}{
+		{JitterFull, 0},
+		{JitterEqual, 4 * time.Second},
+	}
+	for _, tt := range testcases {
+		p := RetryPolicy{
+			RetryInterval: Duration(8 * time.Second),
+			Jitter:        tt.jitter,
+		}
+		for range 100 {
+			actual := p.Interval(1)
+			if actual < tt.min || actual > 8*time.Second {
+				t.Fatalf("%s: Interval(1) got: %s", tt.jitter, actual)
+			}
+		}
+	}
+}
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	timeout := &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}
+	refused := &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}
+	dns := &net.OpError{Op: "dial", Err: &net.DNSError{Name: "x"}}
+	var testcases = []struct {
+		policy   RetryPolicy
+		err      error
+		expected bool
+	}{
+		{RetryPolicy{}, &RunError{Code: 500}, true},
+		{RetryPolicy{}, &RunError{Code: 404}, false},
+		{RetryPolicy{}, errors.New("EOF"), true},
+		{RetryPolicy{RetryableCodes: []string{"429", "5xx"}}, &RunError{Code: 429}, true},
+		{RetryPolicy{RetryableCodes: []string{"429", "5xx"}}, &RunError{Code: 503}, true},
+		{RetryPolicy{RetryableCodes: []string{"429", "5xx"}}, &RunError{Code: 409}, false},
+		{RetryPolicy{RetryableCodes: []string{"1"}}, &RunError{Code: 1}, true},
+		{RetryPolicy{RetryableErrors: []string{"timeout"}}, timeout, true},
+		{RetryPolicy{RetryableErrors: []string{"timeout"}}, refused, false},
+		{RetryPolicy{RetryableErrors: []string{"connection"}}, refused, true},
+		{RetryPolicy{RetryableErrors: []string{"dns"}}, dns, true},
+		{RetryPolicy{RetryableErrors: []string{"dns"}}, errors.New("EOF"), false},
+	}
+	for _, tt := range testcases {
+		actual := tt.policy.Retryable(tt.err)
+		if actual != tt.expected {
+			t.Errorf("Retryable(%v) got: %t, expected: %t", tt.err, actual, tt.expected)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	var testcases = []struct {
+		code     int
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{429, "120", 2 * time.Minute, true},
+		{503, "Mon, 01 Jan 2024 10:00:30 GMT", 30 * time.Second, true},
+		{503, "Mon, 01 Jan 2024 09:00:00 GMT", 0, true},
+		{503, "soon", 0, false},
+		{503, "", 0, false},
+		{500, "120", 0, false},
+	}
+	for _, tt := range testcases {
+		err := &RunError{Code: tt.code, Header: http.Header{}}
+		if tt.value != "" {
+			err.Header.Set("Retry-After", tt.value)
+		}
+		actual, ok := RetryAfter(err, now)
+		if actual != tt.expected || ok != tt.ok {
+			t.Errorf("RetryAfter(%d, %q) got: %s, %t, expected: %s, %t",
+				tt.code, tt.value, actual, ok, tt.expected, tt.ok)
+		}
+	}
+	if _, ok := RetryAfter(errors.New("EOF"), now); ok {
+		t.Error("RetryAfter expected no delay for non run error")
+	}
+}
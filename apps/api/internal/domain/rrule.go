@@ -0,0 +1,518 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	freqYearly = iota
+	freqMonthly
+	freqWeekly
+	freqDaily
+	freqHourly
+	freqMinutely
+)
+
+// maxRRuleDays limits the search of the next recurrence, e.g. 30 Feb.
+const maxRRuleDays = 5 * 366
+
+var (
+	freqNames = []string{
+		"YEARLY", "MONTHLY", "WEEKLY", "DAILY", "HOURLY", "MINUTELY",
+	}
+	icalWeekdays = map[string]time.Weekday{
+		"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday,
+		"WE": time.Wednesday, "TH": time.Thursday, "FR": time.Friday,
+		"SA": time.Saturday,
+	}
+)
+
+// rruleSchedule fires at recurrences of iCalendar RRULE (RFC 5545) since
+// DTSTART, except for EXDATE ones. The recurrences are expanded in wall
+// clock time of DTSTART location.
+type rruleSchedule struct {
+	start    time.Time
+	base     time.Time // wall clock of start in UTC
+	loc      *time.Location
+	freq     int
+	interval int
+	count    int
+	until    time.Time
+	months   []int
+	days     []int
+	weekdays []weekdayNum
+	hours    []int
+	minutes  []int
+	seconds  []int
+	setPos   []int
+	wkst     time.Weekday
+	exdates  map[int64]bool
+	// recurrences of a rule with COUNT in Unix time, expanded once since
+	// counting starts at DTSTART
+	recurrences []int64
+}
+
+// weekdayNum is a BYDAY item, e.g. -1FR stands for the last Friday.
+type weekdayNum struct {
+	weekday time.Weekday
+	n       int
+}
+
+// isRRule reports whether the schedule spec is iCalendar recurrence, e.g.
+// DTSTART:20260105T090000Z RRULE:FREQ=WEEKLY;INTERVAL=2;COUNT=10.
+func isRRule(spec string) bool {
+	return strings.HasPrefix(spec, "DTSTART") || strings.HasPrefix(spec, "RRULE:")
+}
+
+// parseRRule parses DTSTART, RRULE and EXDATE content lines separated by
+// a new line or space, a floating time is evaluated in the location.
+func parseRRule(spec string, loc *time.Location) (*rruleSchedule, error) {
+	s := &rruleSchedule{loc: loc, interval: 1, wkst: time.Monday}
+	var rule string
+	var exdates []string
+	for _, line := range strings.Fields(spec) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid content line: %s", line)
+		}
+		name, params, _ := strings.Cut(name, ";")
+		switch strings.ToUpper(name) {
+		case "DTSTART":
+			if !s.start.IsZero() {
+				return nil, fmt.Errorf("duplicate DTSTART: %s", line)
+			}
+			t, l, err := parseICalTime(params, value, loc)
+			if err != nil {
+				return nil, err
+			}
+			s.start, s.loc = t.In(l), l
+		case "RRULE":
+			if rule != "" {
+				return nil, fmt.Errorf("duplicate RRULE: %s", line)
+			}
+			rule = value
+		case "EXDATE":
+			exdates = append(exdates, line)
+		default:
+			return nil, fmt.Errorf("unsupported property: %s", name)
+		}
+	}
+	if s.start.IsZero() {
+		return nil, errors.New("missing DTSTART")
+	}
+	if rule == "" {
+		return nil, errors.New("missing RRULE")
+	}
+	s.base = time.Date(
+		s.start.Year(), s.start.Month(), s.start.Day(),
+		s.start.Hour(), s.start.Minute(), s.start.Second(), 0, time.UTC)
+	if err := s.parseRule(rule); err != nil {
+		return nil, err
+	}
+	s.exdates = make(map[int64]bool)
+	for _, line := range exdates {
+		name, value, _ := strings.Cut(line, ":")
+		_, params, _ := strings.Cut(name, ";")
+		for _, v := range strings.Split(value, ",") {
+			t, _, err := parseICalTime(params, v, s.loc)
+			if err != nil {
+				return nil, err
+			}
+			s.exdates[t.Unix()] = true
+		}
+	}
+	if s.count > 0 {
+		// a wall clock time in a daylight saving time gap is shifted
+		s.recurrences = s.expand()
+		slices.Sort(s.recurrences)
+		s.recurrences = slices.Compact(s.recurrences)
+	}
+	return s, nil
+}
+
+func (s *rruleSchedule) parseRule(rule string) error {
+	s.freq = -1
+	var err error
+	for _, part := range strings.Split(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("invalid rule part: %s", part)
+		}
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			s.freq = slices.Index(freqNames, strings.ToUpper(value))
+			if s.freq < 0 {
+				return fmt.Errorf("unsupported frequency: %s", value)
+			}
+		case "INTERVAL":
+			s.interval, err = parseNumber(value, 1, 1000, nil)
+		case "COUNT":
+			s.count, err = parseNumber(value, 1, 10000, nil)
+		case "UNTIL":
+			s.until, _, err = parseICalTime("", value, s.loc)
+		case "BYMONTH":
+			s.months, err = parseNumbers(value, 1, 12)
+		case "BYMONTHDAY":
+			s.days, err = parseNumbers(value, -31, 31)
+		case "BYDAY":
+			s.weekdays, err = parseWeekdayNums(value)
+		case "BYHOUR":
+			s.hours, err = parseNumbers(value, 0, 23)
+		case "BYMINUTE":
+			s.minutes, err = parseNumbers(value, 0, 59)
+		case "BYSECOND":
+			s.seconds, err = parseNumbers(value, 0, 59)
+		case "BYSETPOS":
+			s.setPos, err = parseNumbers(value, -366, 366)
+		case "WKST":
+			wd, ok := icalWeekdays[strings.ToUpper(value)]
+			if !ok {
+				return fmt.Errorf("invalid weekday: %s", value)
+			}
+			s.wkst = wd
+		default:
+			return fmt.Errorf("unsupported rule part: %s", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if s.freq < 0 {
+		return errors.New("missing FREQ")
+	}
+	if s.count > 0 && !s.until.IsZero() {
+		return errors.New("COUNT and UNTIL are mutually exclusive")
+	}
+	for _, wn := range s.weekdays {
+		if wn.n != 0 && s.freq != freqMonthly && s.freq != freqYearly {
+			return errors.New("BYDAY ordinal requires MONTHLY or YEARLY frequency")
+		}
+	}
+	// the missing parts are derived from DTSTART
+	if len(s.weekdays) == 0 && len(s.days) == 0 {
+		switch s.freq {
+		case freqYearly:
+			if len(s.months) == 0 {
+				s.months = []int{int(s.base.Month())}
+			}
+			s.days = []int{s.base.Day()}
+		case freqMonthly:
+			s.days = []int{s.base.Day()}
+		case freqWeekly:
+			s.weekdays = []weekdayNum{{weekday: s.base.Weekday()}}
+		}
+	}
+	if len(s.hours) == 0 && s.freq < freqHourly {
+		s.hours = []int{s.base.Hour()}
+	}
+	if len(s.minutes) == 0 && s.freq < freqMinutely {
+		s.minutes = []int{s.base.Minute()}
+	}
+	if len(s.seconds) == 0 {
+		s.seconds = []int{s.base.Second()}
+	}
+	return nil
+}
+
+func (s *rruleSchedule) Next(t time.Time) time.Time {
+	if s.count > 0 {
+		u := t.Unix()
+		i, _ := slices.BinarySearch(s.recurrences, u+1)
+		for ; i < len(s.recurrences); i++ {
+			if u := s.recurrences[i]; !s.exdates[u] {
+				return time.Unix(u, 0).In(s.loc)
+			}
+		}
+		return time.Time{}
+	}
+	p := s.skip(t)
+	w := t.In(s.loc)
+	horizon := time.Date(
+		w.Year(), w.Month(), w.Day()+maxRRuleDays, 0, 0, 0, 0, time.UTC)
+	if b := s.base.AddDate(0, 0, maxRRuleDays); b.After(horizon) {
+		horizon = b
+	}
+	// a few periods are searched anyway since an interval might be longer
+	for i := 0; ; i, p = i+1, p+1 {
+		first, _, _, _ := s.bounds(p)
+		if i > 2 && first.After(horizon) {
+			return time.Time{}
+		}
+		if s.freq >= freqHourly && !s.matchDay(first) {
+			// the rest of periods of the day are empty
+			p = s.nextDay(first) - 1
+			continue
+		}
+		for _, w := range s.period(p) {
+			if w.Before(s.base) {
+				continue
+			}
+			at := s.at(w)
+			if !s.until.IsZero() && at.After(s.until) {
+				return time.Time{}
+			}
+			if at.After(t) && !s.exdates[at.Unix()] {
+				return at
+			}
+		}
+	}
+}
+
+// expand returns up to COUNT recurrences, the search stops once there are
+// none within a few periods and maxRRuleDays since the last one.
+func (s *rruleSchedule) expand() []int64 {
+	recurrences := make([]int64, 0, s.count)
+	last := s.base
+	for i, p := 0, 0; ; i, p = i+1, p+1 {
+		first, _, _, _ := s.bounds(p)
+		if i > 2 && first.After(last.AddDate(0, 0, maxRRuleDays)) {
+			return recurrences
+		}
+		if s.freq >= freqHourly && !s.matchDay(first) {
+			p = s.nextDay(first) - 1
+			continue
+		}
+		for _, w := range s.period(p) {
+			if w.Before(s.base) {
+				continue
+			}
+			recurrences = append(recurrences, s.at(w).Unix())
+			if len(recurrences) == s.count {
+				return recurrences
+			}
+			i, last = 0, w
+		}
+	}
+}
+
+// at returns the recurrence in wall clock time in the location.
+func (s *rruleSchedule) at(w time.Time) time.Time {
+	return time.Date(
+		w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), w.Second(), 0, s.loc)
+}
+
+// skip returns a period prior to one the time falls in, so recurrences of
+// the earlier periods are not expanded.
+func (s *rruleSchedule) skip(t time.Time) int {
+	t = t.In(s.loc)
+	w := time.Date(
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	b := s.base
+	var k int
+	switch s.freq {
+	case freqYearly:
+		k = w.Year() - b.Year()
+	case freqMonthly:
+		k = (w.Year()-b.Year())*12 + int(w.Month()) - int(b.Month())
+	case freqWeekly:
+		k = int(truncateDay(w).Sub(s.weekStart(b)).Hours()) / 24 / 7
+	case freqDaily:
+		k = int(truncateDay(w).Sub(truncateDay(b)).Hours()) / 24
+	case freqHourly:
+		k = int(w.Sub(b.Truncate(time.Hour)).Hours())
+	case freqMinutely:
+		k = int(w.Sub(b.Truncate(time.Minute)).Minutes())
+	}
+	return max(k/s.interval-1, 0)
+}
+
+// period returns recurrences of the period in wall clock time.
+func (s *rruleSchedule) period(p int) []time.Time {
+	first, end, hour, minute := s.bounds(p)
+	var set []time.Time
+	for d := first; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if !s.matchDay(d) {
+			continue
+		}
+		for _, h := range limit(s.hours, hour) {
+			for _, m := range limit(s.minutes, minute) {
+				for _, sec := range s.seconds {
+					set = append(set, time.Date(
+						d.Year(), d.Month(), d.Day(), h, m, sec, 0, time.UTC))
+				}
+			}
+		}
+	}
+	return s.selectPos(set)
+}
+
+// bounds returns days of the period in wall clock time, an hourly or
+// minutely period is limited to the hour or minute of the day.
+func (s *rruleSchedule) bounds(p int) (first, end time.Time, hour, minute int) {
+	b := s.base
+	k := p * s.interval
+	hour, minute = -1, -1
+	switch s.freq {
+	case freqYearly:
+		first = time.Date(b.Year()+k, 1, 1, 0, 0, 0, 0, time.UTC)
+		end = first.AddDate(1, 0, 0)
+	case freqMonthly:
+		first = time.Date(b.Year(), b.Month()+time.Month(k), 1, 0, 0, 0, 0, time.UTC)
+		end = first.AddDate(0, 1, 0)
+	case freqWeekly:
+		first = s.weekStart(b).AddDate(0, 0, 7*k)
+		end = first.AddDate(0, 0, 7)
+	case freqDaily:
+		first = time.Date(b.Year(), b.Month(), b.Day()+k, 0, 0, 0, 0, time.UTC)
+		end = first.AddDate(0, 0, 1)
+	case freqHourly:
+		h := b.Truncate(time.Hour).Add(time.Duration(k) * time.Hour)
+		first, hour = truncateDay(h), h.Hour()
+		end = first.AddDate(0, 0, 1)
+	case freqMinutely:
+		m := b.Truncate(time.Minute).Add(time.Duration(k) * time.Minute)
+		first, hour, minute = truncateDay(m), m.Hour(), m.Minute()
+		end = first.AddDate(0, 0, 1)
+	}
+	return first, end, hour, minute
+}
+
+// nextDay returns the first hourly or minutely period of the day after.
+func (s *rruleSchedule) nextDay(day time.Time) int {
+	unit := time.Hour
+	if s.freq == freqMinutely {
+		unit = time.Minute
+	}
+	n := int(day.AddDate(0, 0, 1).Sub(s.base.Truncate(unit)) / unit)
+	return (n + s.interval - 1) / s.interval
+}
+
+func (s *rruleSchedule) matchDay(d time.Time) bool {
+	if len(s.months) > 0 && !slices.Contains(s.months, int(d.Month())) {
+		return false
+	}
+	if len(s.days) > 0 {
+		last := lastDay(d)
+		if !slices.ContainsFunc(s.days, func(n int) bool {
+			return n == d.Day() || n < 0 && last+n+1 == d.Day()
+		}) {
+			return false
+		}
+	}
+	if len(s.weekdays) > 0 {
+		// an ordinal is within the month unless yearly in any month
+		i, total := d.Day(), lastDay(d)
+		if s.freq == freqYearly && len(s.months) == 0 {
+			i = d.YearDay()
+			total = time.Date(d.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		}
+		if !slices.ContainsFunc(s.weekdays, func(wn weekdayNum) bool {
+			return wn.weekday == d.Weekday() && (wn.n == 0 ||
+				wn.n > 0 && (i-1)/7+1 == wn.n ||
+				wn.n < 0 && (total-i)/7+1 == -wn.n)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *rruleSchedule) selectPos(set []time.Time) []time.Time {
+	if len(s.setPos) == 0 || len(set) == 0 {
+		return set
+	}
+	var selected []time.Time
+	for _, pos := range s.setPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(set) + pos
+		}
+		if i >= 0 && i < len(set) && !slices.Contains(selected, set[i]) {
+			selected = append(selected, set[i])
+		}
+	}
+	slices.SortFunc(selected, time.Time.Compare)
+	return selected
+}
+
+func (s *rruleSchedule) weekStart(d time.Time) time.Time {
+	offset := (int(d.Weekday()) - int(s.wkst) + 7) % 7
+	return truncateDay(d).AddDate(0, 0, -offset)
+}
+
+// limit returns the values unless the value of a period is given, then it
+// returns the value if permitted by the values.
+func limit(values []int, v int) []int {
+	if v < 0 {
+		return values
+	}
+	if len(values) == 0 || slices.Contains(values, v) {
+		return []int{v}
+	}
+	return nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseICalTime parses DATE or DATE-TIME value with optional TZID
+// parameter, a floating time is evaluated in the location.
+func parseICalTime(params, value string, loc *time.Location) (time.Time, *time.Location, error) {
+	for _, p := range strings.Split(params, ";") {
+		if tzid, ok := strings.CutPrefix(p, "TZID="); ok {
+			l, err := LoadLocation(tzid)
+			if err != nil {
+				return time.Time{}, nil, err
+			}
+			loc = l
+		}
+	}
+	layout := "20060102T150405"
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		layout, loc = "20060102T150405Z", time.UTC
+	case len(value) == len("20060102"):
+		layout = "20060102"
+	}
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("invalid date-time: %s", value)
+	}
+	return t, loc, nil
+}
+
+func parseNumbers(s string, min, max int) ([]int, error) {
+	var values []int
+	for _, v := range strings.Split(s, ",") {
+		n, err := parseNumber(v, min, max, nil)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 && min < 0 {
+			return nil, fmt.Errorf("value (0) out of range [%d, %d]: %s", min, max, v)
+		}
+		values = append(values, n)
+	}
+	slices.Sort(values)
+	return slices.Compact(values), nil
+}
+
+func parseWeekdayNums(s string) ([]weekdayNum, error) {
+	var values []weekdayNum
+	for _, v := range strings.Split(s, ",") {
+		if len(v) < 2 {
+			return nil, fmt.Errorf("invalid weekday: %s", v)
+		}
+		i := len(v) - 2
+		wd, ok := icalWeekdays[strings.ToUpper(v[i:])]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday: %s", v)
+		}
+		wn := weekdayNum{weekday: wd}
+		if i > 0 {
+			n, err := strconv.Atoi(v[:i])
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid weekday: %s", v)
+			}
+			wn.n = n
+		}
+		values = append(values, wn)
+	}
+	return values, nil
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRRuleSchedule(t *testing.T) {
+	const biweekly = "DTSTART:20260105T090000Z RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO;COUNT=3"
+	var testcases = []struct {
+		spec     string
+		tz       string
+		after    string
+		expected string
+	}{
+		{biweekly, "", "2026-01-01T00:00:00Z", "2026-01-05T09:00:00Z"},
+		{biweekly, "", "2026-01-05T09:00:00Z", "2026-01-19T09:00:00Z"},
+		{biweekly, "", "2026-01-19T09:00:00Z", "2026-02-02T09:00:00Z"},
+		{biweekly, "", "2026-02-02T09:00:00Z", "0001-01-01T00:00:00Z"},
+		{"DTSTART:20260105T090000Z\nRRULE:FREQ=DAILY\nEXDATE:20260106T090000Z",
+			"", "2026-01-05T09:00:00Z", "2026-01-07T09:00:00Z"},
+		{"DTSTART;TZID=Europe/Berlin:20260105T090000 RRULE:FREQ=DAILY",
+			"", "2026-03-28T12:00:00Z", "2026-03-29T07:00:00Z"},
+		{"DTSTART:20260105T090000 RRULE:FREQ=DAILY",
+			"Europe/Berlin", "2026-01-05T09:00:00Z", "2026-01-06T08:00:00Z"},
+		{"DTSTART:20260105T090000Z RRULE:FREQ=DAILY;UNTIL=20260107T090000Z",
+			"", "2026-01-07T08:00:00Z", "2026-01-07T09:00:00Z"},
+		{"DTSTART:20260105T090000Z RRULE:FREQ=DAILY;UNTIL=20260107T090000Z",
+			"", "2026-01-07T09:00:00Z", "0001-01-01T00:00:00Z"},
+		{"DTSTART:20260101T180000Z RRULE:FREQ=MONTHLY;BYDAY=-1FR",
+			"", "2026-01-01T00:00:00Z", "2026-01-30T18:00:00Z"},
+		{"DTSTART:20260101T090000Z RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			"", "2026-01-31T00:00:00Z", "2026-02-27T09:00:00Z"},
+		{"DTSTART:20240229T090000Z RRULE:FREQ=YEARLY",
+			"", "2024-02-29T09:00:00Z", "2028-02-29T09:00:00Z"},
+		{"DTSTART:20260101T000000Z RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH",
+			"", "2026-01-01T00:00:00Z", "2026-11-26T00:00:00Z"},
+		{"DTSTART:20200101T000000Z RRULE:FREQ=MINUTELY;INTERVAL=15",
+			"", "2026-01-05T09:07:00Z", "2026-01-05T09:15:00Z"},
+		{"DTSTART:20260105T000000Z RRULE:FREQ=HOURLY;INTERVAL=6;BYHOUR=6,18",
+			"", "2026-01-05T07:00:00Z", "2026-01-05T18:00:00Z"},
+		{"DTSTART;VALUE=DATE:20260105 RRULE:FREQ=WEEKLY;BYDAY=MO,WE",
+			"", "2026-01-05T00:00:00Z", "2026-01-07T00:00:00Z"},
+		{"DTSTART:20200101T090000Z RRULE:FREQ=YEARLY;INTERVAL=10",
+			"", "2026-01-05T00:00:00Z", "2030-01-01T09:00:00Z"},
+		{"DTSTART:20260101T000000Z RRULE:FREQ=MINUTELY;INTERVAL=30;BYDAY=SU",
+			"", "2026-01-05T00:00:00Z", "2026-01-11T00:00:00Z"},
+	}
+	for _, tt := range testcases {
+		sched, err := ParseSchedule(tt.spec, tt.tz)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.spec, err)
+		}
+		after, _ := time.Parse(time.RFC3339, tt.after)
+		actual := sched.Next(after).UTC().Format(time.RFC3339)
+		if actual != tt.expected {
+			t.Errorf("%s in %q after %s: got: %s, expected: %s",
+				tt.spec, tt.tz, tt.after, actual, tt.expected)
+		}
+	}
+}
+
+func TestParseRRuleScheduleNever(t *testing.T) {
+	for _, spec := range []string{
+		"DTSTART:20260101T000000Z RRULE:FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+		"DTSTART:20260101T000000Z RRULE:FREQ=MINUTELY;BYMONTH=2;BYMONTHDAY=30",
+		"DTSTART:20260101T000000Z RRULE:FREQ=HOURLY;INTERVAL=24;BYHOUR=6",
+		"DTSTART:20000101T000000Z RRULE:FREQ=DAILY;BYMONTH=4;BYMONTHDAY=31;COUNT=5",
+	} {
+		started := time.Now()
+		sched, err := ParseSchedule(spec, "")
+		if err != nil {
+			t.Fatalf("%s: %s", spec, err)
+		}
+		after, _ := time.Parse(time.RFC3339, "2026-01-05T00:00:00Z")
+		if next := sched.Next(after); !next.IsZero() {
+			t.Errorf("%s: got: %s, expected none", spec, next)
+		}
+		if elapsed := time.Since(started); elapsed > time.Second {
+			t.Errorf("%s: elapsed, got: %s", spec, elapsed)
+		}
+	}
+}
+
+func TestRRuleScheduleCountBetween(t *testing.T) {
+	started := time.Now()
+	sched, err := ParseSchedule(
+		"DTSTART:20260105T000000Z RRULE:FREQ=MINUTELY;COUNT=10000", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, _ := time.Parse(time.RFC3339, "2026-01-05T00:00:00Z")
+
+	ticks := Between(sched, from.Add(-time.Second), from.AddDate(0, 0, 7), MaxUpcomingRuns+1)
+
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("elapsed, got: %s", elapsed)
+	}
+	if len(ticks) != 10000 {
+		t.Fatalf("ticks, got: %d, expected: 10000", len(ticks))
+	}
+	last := from.Add(9999 * time.Minute)
+	if !ticks[9999].Equal(last) {
+		t.Errorf("last, got: %s, expected: %s", ticks[9999], last)
+	}
+}
+
+func TestParseRRuleScheduleFails(t *testing.T) {
+	for _, spec := range []string{
+		"RRULE:FREQ=DAILY",
+		"DTSTART:20260105T090000Z",
+		"DTSTART:2026-01-05 RRULE:FREQ=DAILY",
+		"DTSTART:20260105T090000Z RRULE:INTERVAL=2",
+		"DTSTART:20260105T090000Z RRULE:FREQ=SECONDLY",
+		"DTSTART:20260105T090000Z RRULE:FREQ=DAILY;BYWEEKNO=1",
+		"DTSTART:20260105T090000Z RRULE:FREQ=DAILY;COUNT=2;UNTIL=20260107T090000Z",
+		"DTSTART:20260105T090000Z RRULE:FREQ=WEEKLY;BYDAY=2MO",
+		"DTSTART:20260105T090000Z RRULE:FREQ=MONTHLY;BYMONTHDAY=0",
+		"DTSTART:20260105T090000Z RRULE:FREQ=DAILY EXDATE:tomorrow",
+	} {
+		if _, err := ParseSchedule(spec, ""); err == nil {
+			t.Errorf("%s: expected error", spec)
+		}
+	}
+}
@@ -3,17 +3,53 @@ package domain
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
 )
 
+// MaxMessageLength is the maximum length of a job history message.
+const MaxMessageLength = 1024
+
 type RunError struct {
-	Code int
-	Err  error
+	Code   int
+	Header http.Header
+	Err    error
 }
 
 func (r *RunError) Error() string {
 	return fmt.Sprintf("%d %s", r.Code, r.Err)
 }
 
+func (r *RunError) Unwrap() error {
+	return r.Err
+}
+
+// RunResult describes an outcome of an action run. A runner might return
+// a result along with an error, e.g. a command exited with non-zero code.
+// Header and Body of an HTTP response are kept as is for workflow step
+// outputs, unlike the response snapshot they are not recorded.
+type RunResult struct {
+	Code     int
+	Message  string
+	Response *HTTPResponse
+	Header   http.Header
+	Body     []byte
+}
+
 type Runner interface {
-	Run(ctx context.Context, a *Action) error
+	Run(ctx context.Context, a *Action) (*RunResult, error)
+}
+
+// TruncateMessage shortens s to at most n bytes without breaking UTF-8
+// sequences.
+func TruncateMessage(s string, n int) string {
+	s = strings.ToValidUTF8(s, "?")
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
 }
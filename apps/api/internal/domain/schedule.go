@@ -0,0 +1,267 @@
+package domain
+
+import (
+	"errors"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	UpcomingModeList      = "list"
+	UpcomingModeHistogram = "histogram"
+
+	// MaxUpcomingRange limits a time range of planned runs.
+	MaxUpcomingRange = 7 * 24 * time.Hour
+	// MaxUpcomingRuns limits a number of planned runs in a time range.
+	MaxUpcomingRuns = 10000
+
+	// MaxScheduleJitter limits a window each fire is delayed within.
+	MaxScheduleJitter = time.Hour
+	// MaxSchedules limits a number of additional job schedules.
+	MaxSchedules = 10
+)
+
+// atPrefix denotes a one-off schedule, e.g. @at 2026-01-02T10:30:00Z.
+const atPrefix = "@at "
+
+var errLocalTimezone = errors.New("unknown time zone Local")
+
+// onceSchedule fires at a given time only.
+type onceSchedule struct {
+	at time.Time
+}
+
+func (s *onceSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.at) {
+		return s.at
+	}
+	return time.Time{}
+}
+
+// everySchedule fires at multiples of a delay since zero time, so all
+// instances agree on ticks regardless of when the job is added.
+type everySchedule struct {
+	delay time.Duration
+}
+
+func (s *everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(s.delay).Add(s.delay)
+}
+
+// unionSchedule fires at ticks of any of the schedules, the same tick
+// of several schedules fires once.
+type unionSchedule []cron.Schedule
+
+func (s unionSchedule) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, sched := range s {
+		n := sched.Next(t)
+		if !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return next
+}
+
+// jitterSchedule delays each fire of the schedule by the offset.
+type jitterSchedule struct {
+	sched  cron.Schedule
+	offset time.Duration
+}
+
+func (s *jitterSchedule) Next(t time.Time) time.Time {
+	next := s.sched.Next(t.Add(-s.offset))
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(s.offset)
+}
+
+// WithJitter delays each fire of the schedule by an offset within the
+// jitter window, the offset is stable for the job ID so all instances
+// agree on ticks. A one-off schedule fires on time.
+func WithJitter(sched cron.Schedule, id string, jitter time.Duration) cron.Schedule {
+	if _, ok := sched.(*onceSchedule); ok || jitter <= 0 {
+		return sched
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+	offset := time.Duration(h.Sum64() % uint64(jitter))
+	// cron resolution is a second
+	offset = offset.Truncate(time.Second)
+	if offset == 0 {
+		return sched
+	}
+	return &jitterSchedule{sched: sched, offset: offset}
+}
+
+// windowSchedule fires within a time range only.
+type windowSchedule struct {
+	sched     cron.Schedule
+	notBefore *time.Time
+	notAfter  *time.Time
+}
+
+func (s *windowSchedule) Next(t time.Time) time.Time {
+	if s.notBefore != nil && t.Before(*s.notBefore) {
+		// a tick at not before time is included
+		t = s.notBefore.Add(-time.Nanosecond)
+	}
+	next := s.sched.Next(t)
+	if s.notAfter != nil && !next.Before(*s.notAfter) {
+		return time.Time{}
+	}
+	return next
+}
+
+// WithinWindow limits the schedule to fire since not before time and prior
+// to not after time, either is optional.
+func WithinWindow(sched cron.Schedule, notBefore, notAfter *time.Time) cron.Schedule {
+	if notBefore == nil && notAfter == nil {
+		return sched
+	}
+	return &windowSchedule{sched: sched, notBefore: notBefore, notAfter: notAfter}
+}
+
+// JobSchedule returns the union of the job schedules with its jitter and
+// active window applied. A job fired by triggers only never fires on its
+// own.
+func JobSchedule(j *JobDefinition) (cron.Schedule, error) {
+	var sched cron.Schedule = unionSchedule{}
+	if j.Schedule != "" {
+		var err error
+		if sched, err = ParseSchedule(j.Schedule, j.Timezone); err != nil {
+			return nil, err
+		}
+	}
+	if len(j.Schedules) > 0 {
+		u := unionSchedule{sched}
+		for _, spec := range j.Schedules {
+			sched, err := ParseSchedule(spec, j.Timezone)
+			if err != nil {
+				return nil, err
+			}
+			u = append(u, sched)
+		}
+		sched = u
+	}
+	sched = WithJitter(sched, j.ID, time.Duration(j.Jitter))
+	return WithinWindow(sched, j.NotBefore, j.NotAfter), nil
+}
+
+// IsTick reports whether the schedule fires at the time, e.g. a run on
+// demand is not.
+func IsTick(sched cron.Schedule, t time.Time) bool {
+	return sched.Next(t.Add(-time.Second)).Equal(t)
+}
+
+// Expired reports whether the job is not going to fire anymore.
+func (j *JobDefinition) Expired(now time.Time) bool {
+	return j.NotAfter != nil && !now.Before(*j.NotAfter)
+}
+
+// Inherit applies the collection timezone and jitter unless the job has
+// own ones, the job is not fired while the collection is paused.
+func (j *JobDefinition) Inherit(c *Collection) {
+	if j.Timezone == "" {
+		j.Timezone = c.Timezone
+	}
+	if j.Jitter == 0 {
+		j.Jitter = c.Jitter
+	}
+	if c.PausedUntil != nil &&
+		(j.NotBefore == nil || c.PausedUntil.After(*j.NotBefore)) {
+		j.NotBefore = c.PausedUntil
+	}
+}
+
+// IsOneOff reports whether the schedule spec fires once.
+func IsOneOff(spec string) bool {
+	return strings.HasPrefix(spec, atPrefix)
+}
+
+// LoadLocation returns the location of IANA timezone name, an empty name
+// stands for UTC.
+func LoadLocation(tz string) (*time.Location, error) {
+	if tz == "Local" {
+		return nil, errLocalTimezone
+	}
+	return time.LoadLocation(tz)
+}
+
+// ParseSchedule parses a cron spec, standard or extended one, or iCalendar
+// recurrence to be evaluated in the timezone or a one-off schedule with
+// RFC3339 timestamp.
+func ParseSchedule(spec, tz string) (cron.Schedule, error) {
+	if IsOneOff(spec) {
+		at, err := time.Parse(time.RFC3339, strings.TrimSpace(spec[len(atPrefix):]))
+		if err != nil {
+			return nil, err
+		}
+		return &onceSchedule{at: at}, nil
+	}
+	loc, err := LoadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(spec, extPrefix) {
+		return parseExtended(spec[len(extPrefix):], loc)
+	}
+	if isRRule(spec) {
+		return parseRRule(spec, loc)
+	}
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, err
+	}
+	switch s := sched.(type) {
+	case *cron.SpecSchedule:
+		if s.Location == time.Local {
+			// unless overridden by CRON_TZ prefix
+			s.Location = loc
+		}
+	case cron.ConstantDelaySchedule:
+		return &everySchedule{delay: s.Delay}, nil
+	}
+	return sched, nil
+}
+
+// Upcoming returns up to count times the schedule fires after the time.
+func Upcoming(sched cron.Schedule, after time.Time, count int) []time.Time {
+	ticks := make([]time.Time, 0, count)
+	for t := after; len(ticks) < count; {
+		if t = sched.Next(t); t.IsZero() {
+			break
+		}
+		ticks = append(ticks, t)
+	}
+	return ticks
+}
+
+// Between returns up to limit times the schedule fires after from up to to.
+func Between(sched cron.Schedule, from, to time.Time, limit int) []time.Time {
+	var ticks []time.Time
+	for t := sched.Next(from); !t.IsZero() && !t.After(to); t = sched.Next(t) {
+		if len(ticks) == limit {
+			break
+		}
+		ticks = append(ticks, t)
+	}
+	return ticks
+}
+
+// Histogram returns buckets of the run counts per minute ordered by time.
+func Histogram(counts map[time.Time]int) []*HistogramBucket {
+	buckets := make([]*HistogramBucket, 0, len(counts))
+	for m, n := range counts {
+		buckets = append(buckets, &HistogramBucket{Minute: m, Count: n})
+	}
+	slices.SortFunc(buckets, func(a, b *HistogramBucket) int {
+		return a.Minute.Compare(b.Minute)
+	})
+	return buckets
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	var testcases = []struct {
+		spec     string
+		tz       string
+		after    string
+		expected string
+	}{
+		{"0 9 * * *", "", "2024-03-30T12:00:00Z", "2024-03-31T09:00:00Z"},
+		{"0 9 * * *", "Europe/Berlin", "2024-03-30T12:00:00Z", "2024-03-31T07:00:00Z"},
+		{"0 9 * * *", "Europe/Berlin", "2024-03-29T12:00:00Z", "2024-03-30T08:00:00Z"},
+		{"CRON_TZ=Asia/Tokyo 0 9 * * *", "Europe/Berlin", "2024-03-30T12:00:00Z", "2024-03-31T00:00:00Z"},
+		{"@every 1h", "Europe/Berlin", "2024-03-30T12:00:00Z", "2024-03-30T13:00:00Z"},
+		{"@every 15m", "", "2024-03-30T12:07:10Z", "2024-03-30T12:15:00Z"},
+		{"@at 2024-04-02T10:30:00+02:00", "", "2024-03-30T12:00:00Z", "2024-04-02T08:30:00Z"},
+		{"@at 2024-04-02T10:30:00Z", "", "2024-04-02T10:30:00Z", "0001-01-01T00:00:00Z"},
+	}
+	for _, tt := range testcases {
+		sched, err := ParseSchedule(tt.spec, tt.tz)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.spec, err)
+		}
+		after, _ := time.Parse(time.RFC3339, tt.after)
+		actual := sched.Next(after).UTC().Format(time.RFC3339)
+		if actual != tt.expected {
+			t.Errorf("%s in %q: got: %s, expected: %s", tt.spec, tt.tz, actual, tt.expected)
+		}
+	}
+}
+
+func TestParseScheduleFails(t *testing.T) {
+	for _, tz := range []string{"Local", "Europe/Nowhere"} {
+		if _, err := ParseSchedule("0 9 * * *", tz); err == nil {
+			t.Errorf("%s: expected error", tz)
+		}
+	}
+	for _, spec := range []string{"@at", "@at 2024-04-02", "@at tomorrow"} {
+		if _, err := ParseSchedule(spec, ""); err == nil {
+			t.Errorf("%s: expected error", spec)
+		}
+	}
+}
+
+func TestIsOneOff(t *testing.T) {
+	if !IsOneOff("@at 2024-04-02T10:30:00Z") {
+		t.Error("expected one-off")
+	}
+	if IsOneOff("@every 1h") {
+		t.Error("expected recurring")
+	}
+}
+
+func TestUpcoming(t *testing.T) {
+	var testcases = []struct {
+		spec     string
+		count    int
+		expected []string
+	}{
+		{"0 9 * * 1-5", 3, []string{
+			"2024-04-01T09:00:00Z", "2024-04-02T09:00:00Z", "2024-04-03T09:00:00Z",
+		}},
+		{"@every 30m", 2, []string{"2024-03-30T12:30:00Z", "2024-03-30T13:00:00Z"}},
+		{"@at 2024-04-02T10:30:00Z", 3, []string{"2024-04-02T10:30:00Z"}},
+		{"@at 2024-03-30T10:30:00Z", 3, []string{}},
+	}
+	after, _ := time.Parse(time.RFC3339, "2024-03-30T12:00:00Z")
+	for _, tt := range testcases {
+		sched, err := ParseSchedule(tt.spec, "")
+		if err != nil {
+			t.Fatalf("%s: %s", tt.spec, err)
+		}
+		ticks := Upcoming(sched, after, tt.count)
+		actual := make([]string, 0, len(ticks))
+		for _, tick := range ticks {
+			actual = append(actual, tick.UTC().Format(time.RFC3339))
+		}
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("%s: got: %v, expected: %v", tt.spec, actual, tt.expected)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	sched, _ := ParseSchedule("*/20 9 * * *", "")
+	from, _ := time.Parse(time.RFC3339, "2024-03-30T09:00:00Z")
+	to, _ := time.Parse(time.RFC3339, "2024-03-31T09:20:00Z")
+	var testcases = []struct {
+		limit    int
+		expected []string
+	}{
+		{10, []string{
+			"2024-03-30T09:20:00Z", "2024-03-30T09:40:00Z",
+			"2024-03-31T09:00:00Z", "2024-03-31T09:20:00Z",
+		}},
+		{1, []string{"2024-03-30T09:20:00Z"}},
+	}
+	for _, tt := range testcases {
+		var actual []string
+		for _, tick := range Between(sched, from, to, tt.limit) {
+			actual = append(actual, tick.Format(time.RFC3339))
+		}
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("limit %d: got: %v, expected: %v", tt.limit, actual, tt.expected)
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	counts := make(map[time.Time]int)
+	for _, s := range []string{
+		"2024-03-30T09:02:00Z", "2024-03-30T09:00:00Z", "2024-03-30T09:00:00Z",
+	} {
+		minute, _ := time.Parse(time.RFC3339, s)
+		counts[minute]++
+	}
+
+	actual := Histogram(counts)
+
+	if len(actual) != 2 || actual[0].Count != 2 || actual[1].Count != 1 ||
+		actual[1].Minute.Format(time.RFC3339) != "2024-03-30T09:02:00Z" {
+		t.Errorf("Histogram() got: %v", actual)
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	sched, _ := ParseSchedule("0 * * * *", "")
+	after, _ := time.Parse(time.RFC3339, "2024-03-30T12:00:00Z")
+
+	for _, id := range []string{"my-job-1", "my-job-2", "my-job-3"} {
+		first := WithJitter(sched, id, 5*time.Minute).Next(after)
+		second := WithJitter(sched, id, 5*time.Minute).Next(after)
+		if !first.Equal(second) {
+			t.Errorf("%s: got: %s and %s, expected stable offset", id, first, second)
+		}
+		offset := first.Sub(first.Truncate(time.Hour))
+		if offset < 0 || offset >= 5*time.Minute || offset%time.Second != 0 {
+			t.Errorf("%s: unexpected offset %s", id, offset)
+		}
+		next := WithJitter(sched, id, 5*time.Minute).Next(first)
+		if next.Sub(first) != time.Hour {
+			t.Errorf("%s: got: %s, expected an hour after %s", id, next, first)
+		}
+	}
+
+	if WithJitter(sched, "my-job-1", 0) != sched {
+		t.Error("expected no jitter")
+	}
+	once, _ := ParseSchedule("@at 2024-04-02T10:30:00Z", "")
+	if WithJitter(once, "my-job-1", time.Minute) != once {
+		t.Error("expected one-off schedule to fire on time")
+	}
+}
+
+func TestWithinWindow(t *testing.T) {
+	sched, _ := ParseSchedule("0 9 * * *", "")
+	notBefore, _ := time.Parse(time.RFC3339, "2024-04-01T09:00:00Z")
+	notAfter, _ := time.Parse(time.RFC3339, "2024-04-03T09:00:00Z")
+	var testcases = []struct {
+		notBefore *time.Time
+		notAfter  *time.Time
+		after     string
+		expected  string
+	}{
+		{&notBefore, nil, "2024-03-30T12:00:00Z", "2024-04-01T09:00:00Z"},
+		{&notBefore, &notAfter, "2024-04-01T09:00:00Z", "2024-04-02T09:00:00Z"},
+		{&notBefore, &notAfter, "2024-04-02T09:00:00Z", "0001-01-01T00:00:00Z"},
+		{nil, &notAfter, "2024-03-30T12:00:00Z", "2024-03-31T09:00:00Z"},
+	}
+	for _, tt := range testcases {
+		after, _ := time.Parse(time.RFC3339, tt.after)
+		actual := WithinWindow(sched, tt.notBefore, tt.notAfter).Next(after).
+			UTC().Format(time.RFC3339)
+		if actual != tt.expected {
+			t.Errorf("after %s: got: %s, expected: %s", tt.after, actual, tt.expected)
+		}
+	}
+}
+
+func TestJobScheduleUnion(t *testing.T) {
+	j := &JobDefinition{
+		JobItem:   JobItem{ID: "my-job-1", Schedule: "0 9 * * 1-5"},
+		Schedules: []string{"0 12 * * 0,6", "0 9 * * *"},
+	}
+	sched, err := JobSchedule(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, _ := time.Parse(time.RFC3339, "2024-03-29T00:00:00Z")
+	var actual []string
+	for _, next := range Upcoming(sched, after, 5) {
+		actual = append(actual, next.UTC().Format(time.RFC3339))
+	}
+	expected := []string{
+		"2024-03-29T09:00:00Z",
+		"2024-03-30T09:00:00Z",
+		"2024-03-30T12:00:00Z",
+		"2024-03-31T09:00:00Z",
+		"2024-03-31T12:00:00Z",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got: %v, expected: %v", actual, expected)
+	}
+}
@@ -3,7 +3,9 @@ package domain
 import "time"
 
 type Scheduler interface {
-	SetRunner(f func(*JobDefinition))
+	// SetRunner sets a function called with the job and its scheduled time
+	// on each tick.
+	SetRunner(f func(j *JobDefinition, scheduled time.Time))
 	ListIDs() []string
 	Add(j *JobDefinition) error
 	Remove(id string)
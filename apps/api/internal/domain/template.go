@@ -1,12 +1,40 @@
 package domain
 
 import (
+	"fmt"
 	"html/template"
 	"strings"
+	texttemplate "text/template"
 
 	"github.com/akornatskyy/goext/errorstate"
 )
 
+// Transpose renders the action templates with the variables, a workflow
+// step action is transposed the same way.
+func (a *Action) Transpose(variables map[string]string) (*Action, error) {
+	t := &Action{
+		Type:        a.Type,
+		Assertions:  a.Assertions,
+		Snapshot:    a.Snapshot,
+		RetryPolicy: a.RetryPolicy,
+	}
+	var err error
+	switch a.Type {
+	case ActionTypeExec:
+		t.Command, err = a.Command.Transpose(variables)
+	case ActionTypeSQL:
+		t.Statement, err = a.Statement.Transpose(variables)
+	case ActionTypeWorkflow:
+		t.Workflow, err = a.Workflow.Transpose(variables)
+	default:
+		t.Request, err = a.Request.Transpose(variables)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
 func (req *HTTPRequest) Transpose(variables map[string]string) (*HTTPRequest, error) {
 	e := &errorstate.ErrorState{
 		Domain: domain,
@@ -60,6 +88,115 @@ func (req *HTTPRequest) Transpose(variables map[string]string) (*HTTPRequest, er
 	}, nil
 }
 
+func (cmd *ExecCommand) Transpose(variables map[string]string) (*ExecCommand, error) {
+	e := &errorstate.ErrorState{
+		Domain: domain,
+	}
+	args := make([]string, 0, len(cmd.Args))
+	for _, arg := range cmd.Args {
+		value, err := renderText("arg", arg, variables)
+		if err != nil {
+			e.Add(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "args",
+				Reason:   "template",
+				Message:  err.Error(),
+			})
+			break
+		}
+		args = append(args, value)
+	}
+	env := make([]*NameValuePair, 0, len(cmd.Env))
+	for _, pair := range cmd.Env {
+		value, err := renderText("env value", pair.Value, variables)
+		if err != nil {
+			e.Add(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "env",
+				Reason:   "template",
+				Message:  err.Error(),
+			})
+			break
+		}
+		env = append(env, &NameValuePair{
+			Name:  pair.Name,
+			Value: value,
+		})
+	}
+	stdin, err := renderText("stdin", cmd.Stdin, variables)
+	if err != nil {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "stdin",
+			Reason:   "template",
+			Message:  err.Error(),
+		})
+	}
+	if e.Errors != nil {
+		return nil, e
+	}
+	return &ExecCommand{
+		Path:  cmd.Path,
+		Args:  args,
+		Dir:   cmd.Dir,
+		Env:   env,
+		Stdin: stdin,
+	}, nil
+}
+
+// Transpose resolves the data source name from the variable named by DSN
+// and renders the query template.
+func (st *SQLStatement) Transpose(variables map[string]string) (*SQLStatement, error) {
+	e := &errorstate.ErrorState{
+		Domain: domain,
+	}
+	dsn, ok := variables[st.DSN]
+	if !ok {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "dsn",
+			Reason:   "unknown",
+			Message:  fmt.Sprintf("Unrecognized variable: %s.", st.DSN),
+		})
+	}
+	query, err := renderText("query", st.Query, variables)
+	if err != nil {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "query",
+			Reason:   "template",
+			Message:  err.Error(),
+		})
+	}
+	if e.Errors != nil {
+		return nil, e
+	}
+	return &SQLStatement{
+		DSN:     dsn,
+		Query:   query,
+		MaxRows: st.MaxRows,
+	}, nil
+}
+
+// renderText renders the template as plain text, unlike renderTemplate
+// the values are not escaped, e.g. command args or SQL queries.
+func renderText(name string, text string, variables map[string]string) (string, error) {
+	t, err := texttemplate.New(name).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, variables); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
 func renderTemplate(name string, text string, variables map[string]string) (string, error) {
 	t, err := template.New(name).Parse(text)
 	if err != nil {
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExecCommandTranspose(t *testing.T) {
+	cmd := &ExecCommand{
+		Path: "/usr/local/bin/fetch",
+		Args: []string{"{{.url}}", "--note", "{{.note}}"},
+		Env: []*NameValuePair{
+			{Name: "TOKEN", Value: "{{.token}}"},
+		},
+		Stdin: "<{{.note}}>",
+	}
+	variables := map[string]string{
+		"url":   "https://example.com/?a=1&b=2",
+		"note":  "O'Brien & co",
+		"token": "a+b/c==",
+	}
+
+	actual, err := cmd.Transpose(variables)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &ExecCommand{
+		Path: "/usr/local/bin/fetch",
+		Args: []string{"https://example.com/?a=1&b=2", "--note", "O'Brien & co"},
+		Env: []*NameValuePair{
+			{Name: "TOKEN", Value: "a+b/c=="},
+		},
+		Stdin: "<O'Brien & co>",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got: %+v, expected: %+v", actual, expected)
+	}
+}
+
+func TestSQLStatementTranspose(t *testing.T) {
+	st := &SQLStatement{
+		DSN:     "REPORTING_DSN",
+		Query:   "DELETE FROM audit WHERE age <{{.N}} AND owner = '{{.owner}}'",
+		MaxRows: 5,
+	}
+	variables := map[string]string{
+		"REPORTING_DSN": "postgres://localhost/reports",
+		"N":             "5",
+		"owner":         "O''Brien & co",
+	}
+
+	actual, err := st.Transpose(variables)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &SQLStatement{
+		DSN:     "postgres://localhost/reports",
+		Query:   "DELETE FROM audit WHERE age <5 AND owner = 'O''Brien & co'",
+		MaxRows: 5,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got: %+v, expected: %+v", actual, expected)
+	}
+}
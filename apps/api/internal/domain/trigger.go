@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+const (
+	TriggerCompleted = "completed"
+	TriggerFailed    = "failed"
+	TriggerAny       = "any"
+
+	// MaxTriggers limits a number of triggers of a job.
+	MaxTriggers = 10
+	// MaxTriggerDelay limits a delay of a triggered run.
+	MaxTriggerDelay = 24 * time.Hour
+)
+
+// Fires reports whether the trigger fires on the upstream job run status,
+// a skipped run does not fire any.
+func (t *JobTrigger) Fires(status JobHistoryStatus) bool {
+	switch status {
+	case JobHistoryStatusCompleted:
+		return t.Outcome == TriggerCompleted || t.Outcome == TriggerAny
+	case JobHistoryStatusFailed:
+		return t.Outcome == TriggerFailed || t.Outcome == TriggerAny
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJobTriggerFires(t *testing.T) {
+	var testcases = []struct {
+		outcome string
+		status  JobHistoryStatus
+		fires   bool
+	}{
+		{TriggerCompleted, JobHistoryStatusCompleted, true},
+		{TriggerCompleted, JobHistoryStatusFailed, false},
+		{TriggerFailed, JobHistoryStatusFailed, true},
+		{TriggerFailed, JobHistoryStatusCompleted, false},
+		{TriggerAny, JobHistoryStatusCompleted, true},
+		{TriggerAny, JobHistoryStatusFailed, true},
+		{TriggerAny, JobHistoryStatusSkipped, false},
+	}
+	for _, tt := range testcases {
+		jt := &JobTrigger{JobID: "upstream", Outcome: tt.outcome}
+		if actual := jt.Fires(tt.status); actual != tt.fires {
+			t.Errorf("%s on %s: got: %t, expected: %t",
+				tt.outcome, tt.status, actual, tt.fires)
+		}
+	}
+}
+
+func TestJobScheduleTriggersOnly(t *testing.T) {
+	j := &JobDefinition{
+		Triggers: []*JobTrigger{{JobID: "upstream", Outcome: TriggerAny}},
+	}
+	sched, err := JobSchedule(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := sched.Next(time.Now()); !next.IsZero() {
+		t.Errorf("expected no fires, got: %s", next)
+	}
+}
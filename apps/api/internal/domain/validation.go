@@ -1,13 +1,16 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/akornatskyy/goext/errorstate"
+	"github.com/akornatskyy/scheduler/internal/shared/jsonpath"
 	"github.com/akornatskyy/scheduler/internal/shared/rule"
-	"github.com/robfig/cron/v3"
 )
 
 const (
@@ -23,13 +26,68 @@ var ErrUnableCancelJob = errorstate.Single(&errorstate.Detail{
 	Message:  "Unable to cancel the running job.",
 })
 
+var ErrTooManyRuns = errorstate.Single(&errorstate.Detail{
+	Domain:   domain,
+	Type:     "field",
+	Location: "to",
+	Reason:   "max range",
+	Message:  fmt.Sprintf("Exceeds maximum of %d planned runs.", MaxUpcomingRuns),
+})
+
+var ErrExecDisabled = errorstate.Single(&errorstate.Detail{
+	Domain:   domain,
+	Type:     "field",
+	Location: "type",
+	Reason:   "disabled",
+	Message:  "The EXEC action is not enabled on the server.",
+})
+
+// UnknownCalendarError reports the attached calendar does not exist.
+func UnknownCalendarError(id string) error {
+	return errorstate.Single(&errorstate.Detail{
+		Domain:   domain,
+		Type:     "field",
+		Location: "calendars",
+		Reason:   "not found",
+		Message:  fmt.Sprintf("Unknown calendar: %s.", id),
+	})
+}
+
+// UnknownJobError reports the upstream job of a trigger does not exist.
+func UnknownJobError(id string) error {
+	return errorstate.Single(&errorstate.Detail{
+		Domain:   domain,
+		Type:     "field",
+		Location: "triggers.jobId",
+		Reason:   "not found",
+		Message:  fmt.Sprintf("Unknown job: %s.", id),
+	})
+}
+
+// TriggerCycleError reports the job triggers form a cycle through the
+// upstream job.
+func TriggerCycleError(id string) error {
+	return errorstate.Single(&errorstate.Detail{
+		Domain:   domain,
+		Type:     "field",
+		Location: "triggers",
+		Reason:   "cycle",
+		Message:  fmt.Sprintf("Triggers form a cycle through job: %s.", id),
+	})
+}
+
 func ParseBefore(s string) (time.Time, error) {
+	return ParseTimestamp("before", s)
+}
+
+// ParseTimestamp parses RFC3339 timestamp of a field at location.
+func ParseTimestamp(location, s string) (time.Time, error) {
 	t, err := time.Parse(time.RFC3339, s)
 	if err != nil {
 		return t, errorstate.Single(&errorstate.Detail{
 			Domain:   domain,
 			Type:     "field",
-			Location: "before",
+			Location: location,
 			Reason:   "format",
 			Message:  err.Error(),
 		})
@@ -37,6 +95,20 @@ func ParseBefore(s string) (time.Time, error) {
 	return t, nil
 }
 
+func ParseCount(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return n, errorstate.Single(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "count",
+			Reason:   "format",
+			Message:  fmt.Sprintf("Unrecognized format: %s.", errors.Unwrap(err)),
+		})
+	}
+	return n, nil
+}
+
 func ValidateID(s string) error {
 	e := &errorstate.ErrorState{
 		Domain: domain,
@@ -77,6 +149,56 @@ func ValidateCollection(c *Collection) error {
 
 	rule.ID.Validate(e, c.ID)
 	rule.Name.Validate(e, c.Name)
+	validateTimezone(e, c.Timezone)
+	rule.Priority.Validate(e, c.Priority)
+	rule.MaxRuns.Validate(e, c.MaxRuns)
+	validateScheduleJitter(e, c.Jitter)
+	validateCalendarIDs(e, c.Calendars)
+
+	return e.OrNil()
+}
+
+func ValidateCalendar(c *Calendar) error {
+	e := &errorstate.ErrorState{
+		Domain: domain,
+	}
+
+	rule.ID.Validate(e, c.ID)
+	rule.Name.Validate(e, c.Name)
+	validateTimezone(e, c.Timezone)
+	validateMaxItems(e, "dates", len(c.Dates), MaxCalendarDates)
+	for _, d := range c.Dates {
+		if !rule.CalendarDate.Validate(e, d) {
+			break
+		}
+		if _, err := time.Parse(calendarDateLayout, d); err != nil {
+			e.Add(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "dates",
+				Reason:   "pattern",
+				Message:  fmt.Sprintf("Unrecognized format: %s.", err.Error()),
+			})
+			break
+		}
+	}
+	validateMaxItems(e, "ranges", len(c.Ranges), MaxCalendarRanges)
+	for _, r := range c.Ranges {
+		if r == nil {
+			addRequiredObjectError(e, "ranges")
+			break
+		}
+		if !r.To.After(r.From) {
+			e.Add(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "ranges.to",
+				Reason:   "range",
+				Message:  "Required to be after from.",
+			})
+			break
+		}
+	}
 
 	return e.OrNil()
 }
@@ -126,22 +248,224 @@ func ValidateJobDefinition(j *JobDefinition) error {
 	rule.ID.Validate(e, j.ID)
 	rule.Name.Validate(e, j.Name)
 	rule.CollectionID.Validate(e, j.CollectionID)
-	if rule.Schedule.Validate(e, j.Schedule) {
-		_, err := cron.ParseStandard(j.Schedule)
-		if err != nil {
+	if j.Schedule != "" || len(j.Triggers) == 0 {
+		// a job fired by triggers only has no schedule
+		validateSchedule(e, j.Schedule)
+		if j.State == JobStateEnabled {
+			validateOneOffTime(e, j.Schedule)
+		}
+	}
+	validateSchedules(e, j.Schedule, j.Schedules)
+	validateTimezone(e, j.Timezone)
+	if j.Priority != nil {
+		rule.Priority.Validate(e, *j.Priority)
+	}
+	validateScheduleJitter(e, j.Jitter)
+	validateCalendarIDs(e, j.Calendars)
+	validateTriggers(e, j.Triggers)
+	if j.NotBefore != nil && j.NotAfter != nil && !j.NotAfter.After(*j.NotBefore) {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "notAfter",
+			Reason:   "range",
+			Message:  "Required to be after not before time.",
+		})
+	}
+	if j.Misfire != nil {
+		rule.Misfire.Validate(e, j.Misfire.Policy)
+		rule.MisfireLimit.Validate(e, j.Misfire.Limit)
+	}
+	if j.Concurrency != nil {
+		rule.Concurrency.Validate(e, j.Concurrency.Policy)
+		if j.Concurrency.Policy == ConcurrencyAllow {
+			rule.ConcurrencyLimit.Validate(e, j.Concurrency.Limit)
+		}
+	}
+	validateAction(e, j.Action)
+
+	return e.OrNil()
+}
+
+// ValidateSchedulePreview validates the schedule the same way as the job
+// definition one.
+func ValidateSchedulePreview(spec, tz string, count int) error {
+	e := &errorstate.ErrorState{
+		Domain: domain,
+	}
+
+	validateSchedule(e, spec)
+	validateTimezone(e, tz)
+	rule.PreviewCount.Validate(e, count)
+
+	return e.OrNil()
+}
+
+func ValidateUpcomingQuery(q *UpcomingQuery) error {
+	e := &errorstate.ErrorState{
+		Domain: domain,
+	}
+
+	if q.CollectionID != "" {
+		rule.CollectionID.Validate(e, q.CollectionID)
+	}
+	if !q.To.After(q.From) {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "to",
+			Reason:   "min range",
+			Message:  "Required to be after from.",
+		})
+	} else if q.To.Sub(q.From) > MaxUpcomingRange {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "to",
+			Reason:   "max range",
+			Message: fmt.Sprintf(
+				"Exceeds maximum range of %d days.", MaxUpcomingRange/(24*time.Hour)),
+		})
+	}
+	rule.UpcomingMode.Validate(e, q.Mode)
+
+	return e.OrNil()
+}
+
+func validateSchedule(e *errorstate.ErrorState, spec string) {
+	if rule.Schedule.Validate(e, spec) {
+		validateScheduleFormat(e, "schedule", spec)
+	}
+}
+
+// validateOneOffTime rejects a one-off schedule in the past, the job would
+// never fire. It applies to enabled jobs only, a one-off job is disabled
+// once it has fired and can still be updated.
+func validateOneOffTime(e *errorstate.ErrorState, spec string) {
+	if !IsOneOff(spec) {
+		return
+	}
+	sched, err := ParseSchedule(spec, "")
+	if err != nil || !sched.Next(time.Now()).IsZero() {
+		return
+	}
+	e.Add(&errorstate.Detail{
+		Domain:   domain,
+		Type:     "field",
+		Location: "schedule",
+		Reason:   "past",
+		Message:  "Required to be a time in the future.",
+	})
+}
+
+// validateSchedules validates additional schedules, a one-off schedule
+// cannot be combined with others.
+func validateSchedules(e *errorstate.ErrorState, spec string, specs []string) {
+	validateMaxItems(e, "schedules", len(specs), MaxSchedules)
+	for _, s := range specs {
+		if !rule.Schedules.Validate(e, s) ||
+			!validateScheduleFormat(e, "schedules", s) {
+			break
+		}
+		if IsOneOff(spec) || IsOneOff(s) {
 			e.Add(&errorstate.Detail{
 				Domain:   domain,
 				Type:     "field",
-				Location: "schedule",
-				Reason:   "pattern",
-				Message:  fmt.Sprintf("Unrecognized format: %s.", err.Error()),
+				Location: "schedules",
+				Reason:   "one-off",
+				Message:  "A one-off schedule cannot be combined with others.",
 			})
+			break
 		}
 	}
+}
 
-	validateAction(e, j.Action)
+func validateScheduleFormat(e *errorstate.ErrorState, location, spec string) bool {
+	if _, err := ParseSchedule(spec, ""); err != nil {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: location,
+			Reason:   "pattern",
+			Message:  fmt.Sprintf("Unrecognized format: %s.", err.Error()),
+		})
+		return false
+	}
+	return true
+}
 
-	return e.OrNil()
+func validateTriggers(e *errorstate.ErrorState, triggers []*JobTrigger) {
+	validateMaxItems(e, "triggers", len(triggers), MaxTriggers)
+	for _, t := range triggers {
+		if t == nil {
+			addRequiredObjectError(e, "triggers")
+			break
+		}
+		if !rule.TriggerJobID.Validate(e, t.JobID) ||
+			!rule.TriggerOutcome.Validate(e, t.Outcome) {
+			break
+		}
+		if t.Delay < 0 || time.Duration(t.Delay) > MaxTriggerDelay {
+			e.Add(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "triggers.delay",
+				Reason:   "range",
+				Message: fmt.Sprintf(
+					"The value must fall within the range 0s - %s.", MaxTriggerDelay),
+			})
+			break
+		}
+	}
+}
+
+func validateCalendarIDs(e *errorstate.ErrorState, ids []string) {
+	validateMaxItems(e, "calendars", len(ids), MaxCalendars)
+	for _, id := range ids {
+		if !rule.CalendarID.Validate(e, id) {
+			break
+		}
+	}
+}
+
+func validateMaxItems(e *errorstate.ErrorState, location string, n, max int) {
+	if n > max {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: location,
+			Reason:   "max items",
+			Message:  fmt.Sprintf("Exceeds maximum of %d items.", max),
+		})
+	}
+}
+
+func validateScheduleJitter(e *errorstate.ErrorState, d Duration) {
+	if d < 0 || time.Duration(d) > MaxScheduleJitter {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "jitter",
+			Reason:   "range",
+			Message: fmt.Sprintf(
+				"The value must fall within the range 0s - %s.", MaxScheduleJitter),
+		})
+	}
+}
+
+func validateTimezone(e *errorstate.ErrorState, tz string) {
+	if tz == "" || !rule.Timezone.Validate(e, tz) {
+		return
+	}
+	if _, err := LoadLocation(tz); err != nil {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "timezone",
+			Reason:   "pattern",
+			Message:  fmt.Sprintf("Unrecognized format: %s.", err.Error()),
+		})
+	}
 }
 
 func validateAction(e *errorstate.ErrorState, a *Action) {
@@ -152,10 +476,111 @@ func validateAction(e *errorstate.ErrorState, a *Action) {
 
 	rule.ActionType.Validate(e, a.Type)
 
-	validateHTTPRequest(e, a.Request)
+	switch a.Type {
+	case ActionTypeExec:
+		validateExecCommand(e, a.Command)
+	case ActionTypeSQL:
+		validateSQLStatement(e, a.Statement)
+	case ActionTypeWorkflow:
+		validateWorkflow(e, a.Workflow)
+	default:
+		validateHTTPRequest(e, a.Request)
+		validateHTTPAssertions(e, a.Assertions)
+		validateSnapshotPolicy(e, a.Snapshot)
+	}
 	validateRetryPolicy(e, a.RetryPolicy)
 }
 
+func validateWorkflow(e *errorstate.ErrorState, w *Workflow) {
+	if w == nil {
+		addRequiredObjectError(e, "workflow")
+		return
+	}
+	if len(w.Steps) == 0 {
+		addRequiredObjectError(e, "steps")
+		return
+	}
+	validateMaxItems(e, "steps", len(w.Steps), MaxWorkflowSteps)
+	for _, s := range w.Steps {
+		if s == nil {
+			addRequiredObjectError(e, "steps")
+			return
+		}
+		if !rule.StepName.Validate(e, s.Name) || !rule.StepIf.Validate(e, s.If) {
+			return
+		}
+		if s.Action != nil && s.Action.Type == ActionTypeWorkflow {
+			e.Add(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "steps.action",
+				Reason:   "nested",
+				Message:  "A workflow step cannot be a workflow.",
+			})
+			return
+		}
+		if s.Action != nil && s.Action.RetryPolicy != nil {
+			e.Add(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "steps.action.retryPolicy",
+				Reason:   "unsupported",
+				Message:  "A workflow step is not retried, the workflow is retried as a whole.",
+			})
+			return
+		}
+		validateAction(e, s.Action)
+		validateMaxItems(e, "outputs", len(s.Outputs), MaxStepOutputs)
+		for _, o := range s.Outputs {
+			if !validateStepOutput(e, s.Action, o) {
+				return
+			}
+		}
+	}
+}
+
+func validateStepOutput(e *errorstate.ErrorState, a *Action, o *StepOutput) bool {
+	if o == nil {
+		addRequiredObjectError(e, "outputs")
+		return false
+	}
+	if !rule.OutputName.Validate(e, o.Name) ||
+		!rule.OutputJSON.Validate(e, o.JSON) ||
+		!rule.OutputHeader.Validate(e, o.Header) {
+		return false
+	}
+	var msg string
+	switch {
+	case a != nil && a.Type != ActionTypeHTTP:
+		msg = "Outputs are extracted from HTTP responses only."
+	case (o.JSON == "") == (o.Header == ""):
+		msg = "Required either json or header."
+	}
+	if msg != "" {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "outputs",
+			Reason:   "invalid",
+			Message:  msg,
+		})
+		return false
+	}
+	if o.JSON != "" {
+		if _, err := jsonpath.Parse(o.JSON); err != nil {
+			e.Add(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "outputs.json",
+				Reason:   "pattern",
+				Message:  fmt.Sprintf("Unrecognized format: %s.", err.Error()),
+			})
+			return false
+		}
+	}
+	return true
+}
+
 func validateHTTPRequest(e *errorstate.ErrorState, r *HTTPRequest) {
 	if r == nil {
 		addRequiredObjectError(e, "request")
@@ -171,11 +596,142 @@ func validateHTTPRequest(e *errorstate.ErrorState, r *HTTPRequest) {
 	}
 }
 
+func validateHTTPAssertions(e *errorstate.ErrorState, a *HTTPAssertions) {
+	if a == nil {
+		return
+	}
+
+	for _, code := range a.StatusCodes {
+		if !rule.StatusCode.Validate(e, code) {
+			break
+		}
+	}
+	for _, p := range a.Headers {
+		rule.HeaderName.Validate(e, p.Name)
+		rule.AssertionHeaderValue.Validate(e, p.Value)
+	}
+	rule.AssertionBody.Validate(e, a.Body)
+	if rule.BodyPattern.Validate(e, a.BodyPattern) && a.BodyPattern != "" {
+		if _, err := regexp.Compile(a.BodyPattern); err != nil {
+			e.Add(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "bodyPattern",
+				Reason:   "pattern",
+				Message:  fmt.Sprintf("Unrecognized format: %s.", err.Error()),
+			})
+		}
+	}
+	for _, p := range a.JSON {
+		if rule.JSONPath.Validate(e, p.Name) {
+			if _, err := jsonpath.Parse(p.Name); err != nil {
+				e.Add(&errorstate.Detail{
+					Domain:   domain,
+					Type:     "field",
+					Location: "json.name",
+					Reason:   "pattern",
+					Message:  fmt.Sprintf("Unrecognized format: %s.", err.Error()),
+				})
+			}
+		}
+		rule.JSONValue.Validate(e, p.Value)
+	}
+}
+
+func validateSnapshotPolicy(e *errorstate.ErrorState, p *SnapshotPolicy) {
+	if p == nil {
+		return
+	}
+
+	for _, name := range p.Headers {
+		if !rule.SnapshotHeader.Validate(e, name) {
+			break
+		}
+	}
+	rule.BodyLimit.Validate(e, p.BodyLimit)
+	for _, pattern := range p.Redact {
+		if !rule.RedactPattern.Validate(e, pattern) {
+			break
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			e.Add(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "redact",
+				Reason:   "pattern",
+				Message:  fmt.Sprintf("Unrecognized format: %s.", err.Error()),
+			})
+			break
+		}
+	}
+}
+
+func validateExecCommand(e *errorstate.ErrorState, c *ExecCommand) {
+	if c == nil {
+		addRequiredObjectError(e, "command")
+		return
+	}
+
+	rule.Path.Validate(e, c.Path)
+	for _, arg := range c.Args {
+		if !rule.Arg.Validate(e, arg) {
+			break
+		}
+	}
+	rule.Dir.Validate(e, c.Dir)
+	for _, p := range c.Env {
+		rule.EnvName.Validate(e, p.Name)
+		rule.EnvValue.Validate(e, p.Value)
+	}
+	rule.Stdin.Validate(e, c.Stdin)
+}
+
+func validateSQLStatement(e *errorstate.ErrorState, s *SQLStatement) {
+	if s == nil {
+		addRequiredObjectError(e, "statement")
+		return
+	}
+
+	rule.DSN.Validate(e, s.DSN)
+	rule.Query.Validate(e, s.Query)
+	rule.MaxRows.Validate(e, s.MaxRows)
+}
+
 func validateRetryPolicy(e *errorstate.ErrorState, r *RetryPolicy) {
 	if r == nil {
 		return
 	}
 	rule.RetryCount.Validate(e, r.RetryCount)
+	rule.Backoff.Validate(e, r.Backoff)
+	if r.Multiplier != 0 && (r.Multiplier < 1 || r.Multiplier > 10) {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "multiplier",
+			Reason:   "range",
+			Message:  "The value must fall within the range 1 - 10.",
+		})
+	}
+	if r.MaxInterval < 0 {
+		e.Add(&errorstate.Detail{
+			Domain:   domain,
+			Type:     "field",
+			Location: "maxInterval",
+			Reason:   "min range",
+			Message:  "Required to be a positive duration.",
+		})
+	}
+	rule.Jitter.Validate(e, r.Jitter)
+	for _, c := range r.RetryableCodes {
+		if !rule.RetryableCode.Validate(e, c) {
+			break
+		}
+	}
+	for _, k := range r.RetryableErrors {
+		if !rule.RetryableError.Validate(e, k) {
+			break
+		}
+	}
 }
 
 func addRequiredObjectError(e *errorstate.ErrorState, location string) {
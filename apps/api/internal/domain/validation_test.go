@@ -58,6 +58,24 @@ func TestValidateIDFails(t *testing.T) {
 	}
 }
 
+func TestValidateRetryableCodes(t *testing.T) {
+	var testcases = []struct {
+		code string
+		ok   bool
+	}{
+		{"429", true}, {"503", true}, {"1xx", true}, {"5xx", true},
+		{"0", false}, {"99", false}, {"999", false}, {"600", false},
+		{"6xx", false}, {"50x", false}, {"", false},
+	}
+	for _, tt := range testcases {
+		e := &errorstate.ErrorState{Domain: domain}
+		validateRetryPolicy(e, &RetryPolicy{RetryableCodes: []string{tt.code}})
+		if err := e.OrNil(); (err == nil) != tt.ok {
+			t.Errorf("%q: got err: %v", tt.code, err)
+		}
+	}
+}
+
 func TestValidateCollection(t *testing.T) {
 	var testcases = []string{
 		`ok`, `invalid`,
@@ -80,9 +98,40 @@ func TestValidateCollection(t *testing.T) {
 	}
 }
 
+func TestValidateCalendar(t *testing.T) {
+	var testcases = []string{
+		`ok`, `invalid`,
+	}
+	for _, tt := range testcases {
+		t.Run(tt, func(t *testing.T) {
+			var in struct {
+				C   *Calendar              `json:"calendar"`
+				Err *errorstate.ErrorState `json:"err,omitempty"`
+			}
+			if err := iojson.ReadFile("testdata/validation/calendar/"+tt+".json", &in); err != nil {
+				t.Fatalf("failed to read test data: %v", err)
+			}
+
+			err := ValidateCalendar(in.C)
+			if !sameError(err, in.Err) {
+				t.FailNow()
+			}
+		})
+	}
+}
+
 func TestValidateJobDefinition(t *testing.T) {
 	var testcases = []string{
 		`ok`, `invalid`, `request-null`, // `invalid-uri`, `uri-not-http`,
+		`exec-ok`, `exec-invalid`, `command-null`, `sql-ok`, `sql-invalid`,
+		`assertions-invalid`, `retry-policy-invalid`, `timezone-invalid`,
+		`at-ok`, `at-invalid`, `at-past`, `at-past-disabled`,
+		`misfire-invalid`,
+		`concurrency-invalid`, `concurrency-unknown`, `jitter-invalid`,
+		`window-invalid`, `ext-ok`, `ext-invalid`, `rrule-ok`,
+		`rrule-invalid`, `schedules-ok`, `schedules-invalid`,
+		`schedules-one-off`, `triggers-ok`, `triggers-invalid`, `workflow-ok`,
+		`workflow-invalid`, `workflow-retry-policy`,
 	}
 	for _, tt := range testcases {
 		t.Run(tt, func(t *testing.T) {
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"github.com/akornatskyy/goext/errorstate"
+)
+
+const (
+	// MaxWorkflowSteps limits a number of steps of a workflow.
+	MaxWorkflowSteps = 10
+	// MaxStepOutputs limits a number of outputs of a workflow step.
+	MaxStepOutputs = 10
+)
+
+// Runs reports whether the action, or a workflow step, is of the type.
+func (a *Action) Runs(actionType string) bool {
+	if a.Type == actionType {
+		return true
+	}
+	if a.Type == ActionTypeWorkflow && a.Workflow != nil {
+		for _, step := range a.Workflow.Steps {
+			if step.Action.Type == actionType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Transpose checks templates of the steps and keeps the variables, the
+// steps are transposed as they run since outputs of the previous ones are
+// known then.
+func (w *Workflow) Transpose(variables map[string]string) (*Workflow, error) {
+	for _, step := range w.Steps {
+		if _, err := step.Action.Transpose(variables); err != nil {
+			return nil, err
+		}
+		if _, err := step.Holds(variables); err != nil {
+			return nil, errorstate.Single(&errorstate.Detail{
+				Domain:   domain,
+				Type:     "field",
+				Location: "steps.if",
+				Reason:   "template",
+				Message:  err.Error(),
+			})
+		}
+	}
+	return &Workflow{Steps: w.Steps, variables: variables}, nil
+}
+
+// Variables returns the variables the workflow is transposed with.
+func (w *Workflow) Variables() map[string]string {
+	return w.variables
+}
+
+// Holds reports whether the step condition, if any, holds.
+func (s *WorkflowStep) Holds(variables map[string]string) (bool, error) {
+	if s.If == "" {
+		return true, nil
+	}
+	v, err := renderText("if", "{{if "+s.If+"}}true{{end}}", variables)
+	if err != nil {
+		return false, err
+	}
+	return v == "true", nil
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+)
+
+func newTestWorkflow(steps ...*WorkflowStep) *Action {
+	return &Action{Type: ActionTypeWorkflow, Workflow: &Workflow{Steps: steps}}
+}
+
+func TestWorkflowTranspose(t *testing.T) {
+	w := &Workflow{Steps: []*WorkflowStep{{
+		Name: "sync",
+		If:   "eq .Env",
+		Action: &Action{
+			Type:    ActionTypeHTTP,
+			Request: &HTTPRequest{URI: "https://api/sync"},
+		},
+	}}}
+
+	if _, err := w.Transpose(map[string]string{"Env": "prod"}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestActionRuns(t *testing.T) {
+	a := newTestWorkflow(&WorkflowStep{
+		Name:   "notify",
+		Action: &Action{Type: ActionTypeExec},
+	})
+
+	if !a.Runs(ActionTypeExec) || !a.Runs(ActionTypeWorkflow) {
+		t.Error("expected to run exec and workflow")
+	}
+	if a.Runs(ActionTypeSQL) {
+		t.Error("unexpected to run sql")
+	}
+}
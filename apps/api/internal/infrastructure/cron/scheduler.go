@@ -14,7 +14,9 @@ type cronSheduler struct {
 	mu     sync.Mutex
 	c      *cron.Cron
 	jobs   map[string]*cronJob
-	runner func(*domain.JobDefinition)
+	runner func(*domain.JobDefinition, time.Time)
+	// elector is set in leader mode only
+	elector domain.Elector
 }
 
 type cronJob struct {
@@ -29,7 +31,15 @@ func New() domain.Scheduler {
 	return &cronSheduler{c: c, jobs: make(map[string]*cronJob)}
 }
 
-func (s *cronSheduler) SetRunner(f func(*domain.JobDefinition)) {
+// NewLeader returns a scheduler that fires jobs only while this instance
+// is elected as the leader, other instances stay hot standbys.
+func NewLeader(e domain.Elector) domain.Scheduler {
+	s := New().(*cronSheduler)
+	s.elector = e
+	return s
+}
+
+func (s *cronSheduler) SetRunner(f func(*domain.JobDefinition, time.Time)) {
 	s.runner = f
 }
 
@@ -48,23 +58,25 @@ func (s *cronSheduler) Add(j *domain.JobDefinition) error {
 	s.mu.Lock()
 	cj := s.jobs[j.ID]
 	if cj != nil {
-		if j.Updated.Equal(cj.j.Updated) {
+		if j.Updated.Equal(cj.j.Updated) && j.Timezone == cj.j.Timezone &&
+			j.Jitter == cj.j.Jitter && equalTime(j.NotBefore, cj.j.NotBefore) {
 			return nil
 		}
 		s.c.Remove(cj.id)
 		delete(s.jobs, j.ID)
 	}
 
-	id, err := s.c.AddFunc(j.Schedule, func() {
-		s.Run(j)
-	})
+	// jitter spreads jobs fired at the same time, e.g. at the top of the
+	// hour, the active window holds off the job until not before time
+	sched, err := domain.JobSchedule(j)
 	if err != nil {
 		return err
 	}
-	s.jobs[j.ID] = &cronJob{
-		id: id,
-		j:  j,
-	}
+	cj = &cronJob{j: j}
+	cj.id = s.c.Schedule(sched, cron.FuncJob(func() {
+		s.Run(cj)
+	}))
+	s.jobs[j.ID] = cj
 	return nil
 }
 
@@ -88,10 +100,17 @@ func (s *cronSheduler) NextRun(id string) *time.Time {
 		return nil
 	}
 	e := s.c.Entry(cj.id)
+	if e.Next.IsZero() {
+		// e.g. a one-off schedule already fired
+		return nil
+	}
 	return &e.Next
 }
 
 func (s *cronSheduler) Start() {
+	if s.elector != nil {
+		s.elector.Start()
+	}
 	s.c.Start()
 	log.Print("scheduler started")
 }
@@ -99,10 +118,52 @@ func (s *cronSheduler) Start() {
 func (s *cronSheduler) Stop() {
 	log.Println("scheduler is awaiting jobs to finish")
 	<-s.c.Stop().Done()
+	if s.elector != nil {
+		s.elector.Stop()
+	}
 
 	log.Print("scheduler stopped")
 }
 
-func (s *cronSheduler) Run(j *domain.JobDefinition) {
-	s.runner(j)
+// Run calls runner with the job and the tick time it is fired for.
+func (s *cronSheduler) Run(cj *cronJob) {
+	s.mu.Lock()
+	id := cj.id
+	s.mu.Unlock()
+	// the entry previous time is updated by the time it is available
+	e := s.c.Entry(id)
+	scheduled := e.Prev
+	if scheduled.IsZero() {
+		// e.g. the job is removed meanwhile, there is no tick all
+		// instances agree on, so the fire is dropped
+		log.Printf("job %s: dropped fire, no tick", cj.j.ID)
+		return
+	}
+	if e.Next.IsZero() {
+		// e.g. not after time is reached or a one-off schedule fired
+		s.expire(cj)
+	}
+	if s.elector != nil && !s.elector.IsLeader() {
+		return
+	}
+	s.runner(cj.j, scheduled)
+}
+
+// expire removes the job entry unless it is re-added meanwhile.
+func (s *cronSheduler) expire(cj *cronJob) {
+	defer s.mu.Unlock()
+	s.mu.Lock()
+	if s.jobs[cj.j.ID] != cj {
+		return
+	}
+	s.c.Remove(cj.id)
+	delete(s.jobs, cj.j.ID)
+	log.Printf("job %s: removed from scheduler, no more runs", cj.j.ID)
+}
+
+func equalTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
 }
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+	"github.com/akornatskyy/scheduler/internal/shared/jsonpath"
+)
+
+// assert checks the response against assertions and returns an error
+// describing the first failed one.
+func assert(a *domain.HTTPAssertions, resp *http.Response, body []byte) error {
+	if len(a.StatusCodes) > 0 && !slices.Contains(a.StatusCodes, resp.StatusCode) {
+		return fmt.Errorf("assertion failed: status code %d, expected one of %v",
+			resp.StatusCode, a.StatusCodes)
+	}
+	for _, h := range a.Headers {
+		values, ok := resp.Header[http.CanonicalHeaderKey(h.Name)]
+		if !ok {
+			return fmt.Errorf("assertion failed: header %s is missing", h.Name)
+		}
+		if h.Value != "" && !slices.Contains(values, h.Value) {
+			return fmt.Errorf("assertion failed: header %s %q, expected %q",
+				h.Name, values[0], h.Value)
+		}
+	}
+	if a.Body != "" && !bytes.Contains(body, []byte(a.Body)) {
+		return fmt.Errorf("assertion failed: body does not contain %q", a.Body)
+	}
+	if a.BodyPattern != "" {
+		r, err := regexp.Compile(a.BodyPattern)
+		if err != nil {
+			return err
+		}
+		if !r.Match(body) {
+			return fmt.Errorf("assertion failed: body does not match %q", a.BodyPattern)
+		}
+	}
+	if len(a.JSON) == 0 {
+		return nil
+	}
+	doc, err := jsonpath.Decode(body)
+	if err != nil {
+		return fmt.Errorf("assertion failed: body is not JSON: %w", err)
+	}
+	for _, p := range a.JSON {
+		path, err := jsonpath.Parse(p.Name)
+		if err != nil {
+			return err
+		}
+		v, ok := path.Lookup(doc)
+		if !ok {
+			return fmt.Errorf("assertion failed: %s is missing", p.Name)
+		}
+		if actual := jsonpath.String(v); actual != p.Value {
+			return fmt.Errorf("assertion failed: %s %s, expected %s",
+				p.Name, actual, p.Value)
+		}
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/akornatskyy/goext/httpjson"
+	"github.com/akornatskyy/scheduler/internal/core"
 	"github.com/akornatskyy/scheduler/internal/domain"
 	"github.com/julienschmidt/httprouter"
 )
@@ -33,7 +34,7 @@ func (s *Server) createCollection() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var collection domain.Collection
 		collection.State = domain.CollectionStateEnabled
-		if err := httpjson.Decode(r, &collection, 140); err != nil {
+		if err := httpjson.Decode(r, &collection, 1024); err != nil {
 			httpjson.Encode(w, err, http.StatusUnprocessableEntity)
 			return
 		}
@@ -74,7 +75,7 @@ func (s *Server) patchCollection() httprouter.Handle {
 			w.WriteHeader(http.StatusPreconditionFailed)
 			return
 		}
-		if err := httpjson.Decode(r, &c, 140); err != nil {
+		if err := httpjson.Decode(r, &c, 1024); err != nil {
 			httpjson.Encode(w, err, http.StatusUnprocessableEntity)
 			return
 		}
@@ -206,6 +207,102 @@ func (s *Server) deleteVariable() httprouter.Handle {
 	}
 }
 
+func (s *Server) listCalendars() http.HandlerFunc {
+	type Response struct {
+		Items []*domain.CalendarItem `json:"items"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := s.Service.ListCalendars()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resp := &Response{
+			Items: items,
+		}
+		httpjson.Encode(w, resp, http.StatusOK)
+	}
+}
+
+func (s *Server) createCalendar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var c domain.Calendar
+		if err := httpjson.Decode(r, &c, 16384); err != nil {
+			httpjson.Encode(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+		if err := s.Service.CreateCalendar(&c); err != nil {
+			writeError(w, err)
+			return
+		}
+		httpjson.Encode(w, c.ID, http.StatusCreated)
+	}
+}
+
+func (s *Server) retrieveCalendar() httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		c, err := s.Service.RetrieveCalendar(p.ByName("id"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		etag := c.ETag()
+		if etag == r.Header.Get("If-None-Match") {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Add("ETag", etag)
+		httpjson.Encode(w, c, http.StatusOK)
+	}
+}
+
+func (s *Server) patchCalendar() httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		c, err := s.Service.RetrieveCalendar(p.ByName("id"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		etag := r.Header.Get("If-Match")
+		if etag != "" && etag != c.ETag() {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if err := httpjson.Decode(r, &c, 16384); err != nil {
+			httpjson.Encode(w, err, http.StatusUnprocessableEntity)
+			return
+		}
+		if err := s.Service.UpdateCalendar(c); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) deleteCalendar() httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		id := p.ByName("id")
+		etag := r.Header.Get("If-Match")
+		if etag != "" {
+			c, err := s.Service.RetrieveCalendar(id)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			if etag != c.ETag() {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+		}
+		if err := s.Service.DeleteCalendar(id); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
 func (s *Server) listJobs() http.HandlerFunc {
 	type Response struct {
 		Items []*domain.JobItem `json:"items"`
@@ -232,7 +329,7 @@ func (s *Server) listJobs() http.HandlerFunc {
 func (s *Server) createJob() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var job domain.JobDefinition
-		if err := httpjson.Decode(r, &job, 4096); err != nil {
+		if err := httpjson.Decode(r, &job, 8192); err != nil {
 			httpjson.Encode(w, err, http.StatusUnprocessableEntity)
 			return
 		}
@@ -273,7 +370,7 @@ func (s *Server) patchJob() httprouter.Handle {
 			w.WriteHeader(http.StatusPreconditionFailed)
 			return
 		}
-		if err := httpjson.Decode(r, &j, 4096); err != nil {
+		if err := httpjson.Decode(r, &j, 8192); err != nil {
 			httpjson.Encode(w, err, http.StatusUnprocessableEntity)
 			return
 		}
@@ -406,6 +503,17 @@ func (s *Server) listJobHistory() httprouter.Handle {
 	}
 }
 
+func (s *Server) retrieveJobHistory() httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		jh, err := s.Service.RetrieveJobHistory(p.ByName("id"), p.ByName("historyId"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		httpjson.Encode(w, jh, http.StatusOK)
+	}
+}
+
 func (s *Server) deleteJobHistory() httprouter.Handle {
 	return func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		id := p.ByName("id")
@@ -439,16 +547,98 @@ func (s *Server) deleteJobHistory() httprouter.Handle {
 	}
 }
 
+func (s *Server) previewSchedule() http.HandlerFunc {
+	type Response struct {
+		Items []time.Time `json:"items"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		count := core.DefaultPreviewCount
+		if c := q.Get("count"); c != "" {
+			var err error
+			count, err = domain.ParseCount(c)
+			if err != nil {
+				httpjson.Encode(w, err, http.StatusBadRequest)
+				return
+			}
+		}
+		items, err := s.Service.PreviewSchedule(q.Get("expr"), q.Get("tz"), count)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resp := &Response{
+			Items: items,
+		}
+		httpjson.Encode(w, resp, http.StatusOK)
+	}
+}
+
+func (s *Server) listUpcomingRuns() http.HandlerFunc {
+	type Response struct {
+		Items interface{} `json:"items"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		query := &domain.UpcomingQuery{
+			CollectionID: q.Get("collectionId"),
+			From:         time.Now().UTC(),
+			Mode:         domain.UpcomingModeList,
+		}
+		if m := q.Get("mode"); m != "" {
+			query.Mode = m
+		}
+		var err error
+		if from := q.Get("from"); from != "" {
+			if query.From, err = domain.ParseTimestamp("from", from); err != nil {
+				httpjson.Encode(w, err, http.StatusBadRequest)
+				return
+			}
+		}
+		query.To = query.From.Add(time.Hour)
+		if to := q.Get("to"); to != "" {
+			if query.To, err = domain.ParseTimestamp("to", to); err != nil {
+				httpjson.Encode(w, err, http.StatusBadRequest)
+				return
+			}
+		}
+		var items interface{}
+		if query.Mode == domain.UpcomingModeHistogram {
+			items, err = s.Service.CountUpcomingRuns(query)
+		} else {
+			items, err = s.Service.ListUpcomingRuns(query)
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		resp := &Response{
+			Items: items,
+		}
+		httpjson.Encode(w, resp, http.StatusOK)
+	}
+}
+
 func (s *Server) health() http.HandlerFunc {
 	const up = "{\"status\":\"up\"}"
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-		if err := s.Service.Health(); err != nil {
+		err := s.Service.Health()
+		var leader string
+		if err == nil {
+			leader, err = s.Service.Leader()
+		}
+		if err != nil {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			//nolint:errcheck
 			_, _ = fmt.Fprintf(w, "{\"status\":\"down\",\"message\":%q}", err.Error())
 			return
 		}
+		if leader != "" {
+			//nolint:errcheck
+			_, _ = fmt.Fprintf(w, "{\"status\":\"up\",\"leader\":%q}", leader)
+			return
+		}
 		//nolint:errcheck
 		_, _ = fmt.Fprint(w, up)
 	}
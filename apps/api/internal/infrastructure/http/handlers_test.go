@@ -38,13 +38,18 @@ type (
 	}
 
 	mockRepository struct {
-		Collections []*domain.CollectionItem `json:"collections"`
-		Collection  *domain.Collection       `json:"collection"`
-		Jobs        []*domain.JobItem        `json:"jobs"`
-		Job         *domain.JobDefinition    `json:"job"`
-		JobStatus   *domain.JobStatus        `json:"jobStatus"`
-		JobHistory  []*domain.JobHistory     `json:"jobHistory"`
-		Err         string                   `json:"err"`
+		Collections []*domain.CollectionItem        `json:"collections"`
+		Collection  *domain.Collection              `json:"collection"`
+		Calendars   []*domain.CalendarItem          `json:"calendars"`
+		Calendar    *domain.Calendar                `json:"calendar"`
+		Jobs        []*domain.JobItem               `json:"jobs"`
+		Job         *domain.JobDefinition           `json:"job"`
+		Triggers    map[string][]*domain.JobTrigger `json:"triggers"`
+		JobStatus   *domain.JobStatus               `json:"jobStatus"`
+		JobHistory  []*domain.JobHistory            `json:"jobHistory"`
+		HistoryItem *domain.JobHistory              `json:"historyItem"`
+		LeaderName  string                          `json:"leader"`
+		Err         string                          `json:"err"`
 	}
 
 	mockScheduler struct {
@@ -100,6 +105,9 @@ func runTest(t *testing.T, in, golden string) {
 			Runners:    map[string]domain.Runner{},
 		},
 	}
+	if i.Mock != nil && i.Mock.LeaderName != "" {
+		srv.Service.Elector = i.Mock
+	}
 	srv.Routes().ServeHTTP(w, r)
 
 	actual := result{
@@ -215,6 +223,29 @@ func (r *mockRepository) DeleteVariable(id string) error {
 	return r.err("delete-variable")
 }
 
+func (r *mockRepository) ListCalendars() ([]*domain.CalendarItem, error) {
+	return r.Calendars, r.err("list-calendars")
+}
+
+func (r *mockRepository) CreateCalendar(c *domain.Calendar) error {
+	return r.err("create-calendar")
+}
+
+func (r *mockRepository) RetrieveCalendar(id string) (*domain.Calendar, error) {
+	if r.Calendar == nil && r.Err == "" {
+		return nil, domain.ErrNotFound
+	}
+	return r.Calendar, r.err("retrieve-calendar")
+}
+
+func (r *mockRepository) UpdateCalendar(c *domain.Calendar) error {
+	return r.err("update-calendar")
+}
+
+func (r *mockRepository) DeleteCalendar(id string) error {
+	return r.err("delete-calendar")
+}
+
 func (r *mockRepository) ListJobs(collectionID string, fields []string) ([]*domain.JobItem, error) {
 	return r.Jobs, r.err("list-jobs")
 }
@@ -235,6 +266,45 @@ func (r *mockRepository) DeleteJob(id string) error {
 	return r.err("delete-job")
 }
 
+func (r *mockRepository) ListEnabledJobs(collectionID string) ([]*domain.JobDefinition, error) {
+	enabled := make(map[string]bool)
+	for _, c := range r.Collections {
+		enabled[c.ID] = c.State == domain.CollectionStateEnabled &&
+			(collectionID == "" || c.ID == collectionID)
+	}
+	jobs := make([]*domain.JobDefinition, 0)
+	for _, item := range r.Jobs {
+		if item.State != domain.JobStateEnabled || !enabled[item.CollectionID] {
+			continue
+		}
+		j := *r.Job
+		j.Inherit(r.Collection)
+		jobs = append(jobs, &j)
+	}
+	return jobs, r.err("list-enabled-jobs")
+}
+
+func (r *mockRepository) ListJobTriggers() (map[string][]*domain.JobTrigger, error) {
+	triggers := make(map[string][]*domain.JobTrigger)
+	for id, t := range r.Triggers {
+		triggers[id] = t
+	}
+	return triggers, r.err("list-job-triggers")
+}
+
+func (r *mockRepository) ListDependentJobs(upstreamID string) (map[string][]*domain.JobTrigger, error) {
+	triggers := make(map[string][]*domain.JobTrigger)
+	for id, tt := range r.Triggers {
+		for _, t := range tt {
+			if t.JobID == upstreamID {
+				triggers[id] = tt
+				break
+			}
+		}
+	}
+	return triggers, r.err("list-dependent-jobs")
+}
+
 func (r *mockRepository) RetrieveJobStatus(id string) (*domain.JobStatus, error) {
 	return r.JobStatus, r.err("retrieve-job-status")
 }
@@ -247,14 +317,32 @@ func (r *mockRepository) ResetJobStatus(id string) error {
 	return r.err("reset-job-status")
 }
 
-func (r *mockRepository) AcquireJob(id string, deadline time.Duration) error {
-	return r.err("acquire-job")
+func (r *mockRepository) AcquireJob(
+	id string, deadline time.Duration, p *domain.ConcurrencyPolicy,
+) (int, error) {
+	return 0, r.err("acquire-job")
+}
+
+func (r *mockRepository) EnqueueJobRun(id string, scheduled time.Time) error {
+	return r.err("enqueue-job-run")
+}
+
+func (r *mockRepository) ClaimJobRun() (*domain.JobRun, error) {
+	return nil, domain.ErrNotFound
+}
+
+func (r *mockRepository) FinishJobRun(run *domain.JobRun) error {
+	return nil
 }
 
 func (r *mockRepository) ListJobHistory(id string) ([]*domain.JobHistory, error) {
 	return r.JobHistory, r.err("retrieve-job-history")
 }
 
+func (r *mockRepository) RetrieveJobHistory(jobID, id string) (*domain.JobHistory, error) {
+	return r.HistoryItem, r.err("retrieve-job-history-item")
+}
+
 func (r *mockRepository) AddJobHistory(jh *domain.JobHistory) error {
 	return r.err("add-job-history")
 }
@@ -263,7 +351,21 @@ func (r *mockRepository) DeleteJobHistory(id string, before time.Time) error {
 	return r.err("delete-job-history")
 }
 
-func (r *mockScheduler) SetRunner(f func(*domain.JobDefinition)) {
+func (r *mockRepository) Start() {
+}
+
+func (r *mockRepository) Stop() {
+}
+
+func (r *mockRepository) IsLeader() bool {
+	return false
+}
+
+func (r *mockRepository) Leader() (string, error) {
+	return r.LeaderName, r.err("leader")
+}
+
+func (r *mockScheduler) SetRunner(f func(*domain.JobDefinition, time.Time)) {
 }
 
 func (r *mockScheduler) ListIDs() []string {
@@ -21,6 +21,12 @@ func (s *Server) Routes() http.Handler {
 	r.Handle("PATCH", "/variables/:id", s.patchVariable())
 	r.Handle("DELETE", "/variables/:id", s.deleteVariable())
 
+	r.HandlerFunc("GET", "/calendars", ETagHandler(s.listCalendars()))
+	r.HandlerFunc("POST", "/calendars", s.createCalendar())
+	r.Handle("GET", "/calendars/:id", s.retrieveCalendar())
+	r.Handle("PATCH", "/calendars/:id", s.patchCalendar())
+	r.Handle("DELETE", "/calendars/:id", s.deleteCalendar())
+
 	r.HandlerFunc("GET", "/jobs", ETagHandler(s.listJobs()))
 	r.HandlerFunc("POST", "/jobs", s.createJob())
 	r.Handle("GET", "/jobs/:id", s.retrieveJob())
@@ -32,6 +38,10 @@ func (s *Server) Routes() http.Handler {
 
 	r.Handle("GET", "/jobs/:id/history", s.listJobHistory())
 	r.Handle("DELETE", "/jobs/:id/history", s.deleteJobHistory())
+	r.Handle("GET", "/jobs/:id/history/:historyId", s.retrieveJobHistory())
+
+	r.HandlerFunc("GET", "/schedule/preview", s.previewSchedule())
+	r.HandlerFunc("GET", "/schedule/upcoming", s.listUpcomingRuns())
 
 	r.HandlerFunc("GET", "/health", s.health())
 
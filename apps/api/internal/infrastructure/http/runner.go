@@ -26,7 +26,7 @@ func NewRunner() domain.Runner {
 	}
 }
 
-func (runner *httpRunner) Run(ctx context.Context, a *domain.Action) error {
+func (runner *httpRunner) Run(ctx context.Context, a *domain.Action) (*domain.RunResult, error) {
 	r := a.Request
 	var reader io.Reader
 	if r.Body != "" {
@@ -34,7 +34,7 @@ func (runner *httpRunner) Run(ctx context.Context, a *domain.Action) error {
 	}
 	req, err := http.NewRequest(r.Method, r.URI, reader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	for _, p := range r.Headers {
 		req.Header.Add(p.Name, p.Value)
@@ -43,18 +43,41 @@ func (runner *httpRunner) Run(ctx context.Context, a *domain.Action) error {
 	resp, err := runner.client.Do(req.WithContext(ctx))
 	if err != nil {
 		log.Printf("%s %s - %s", r.Method, r.URI, err)
-		return err
+		return nil, err
 	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("WARN: failed to close response body: %v", err)
+		}
+	}()
 	body, err := io.ReadAll(resp.Body)
 	log.Printf("%s %s - %d %d", r.Method, r.URI, resp.StatusCode, len(body))
 	if err != nil {
-		return err
+		return nil, err
+	}
+	res := &domain.RunResult{
+		Code:     resp.StatusCode,
+		Response: snapshot(a.Snapshot, resp, body),
+		Header:   resp.Header,
+		Body:     body,
+	}
+	if a.Assertions == nil || len(a.Assertions.StatusCodes) == 0 {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return res, &domain.RunError{
+				Code:   resp.StatusCode,
+				Header: resp.Header,
+				Err:    errors.New(http.StatusText(resp.StatusCode)),
+			}
+		}
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return &domain.RunError{
-			Code: resp.StatusCode,
-			Err:  errors.New(http.StatusText(resp.StatusCode)),
+	if a.Assertions != nil {
+		if err := assert(a.Assertions, resp, body); err != nil {
+			return res, &domain.RunError{
+				Code:   resp.StatusCode,
+				Header: resp.Header,
+				Err:    err,
+			}
 		}
 	}
-	return nil
+	return res, nil
 }
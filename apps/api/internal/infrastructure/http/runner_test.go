@@ -74,7 +74,7 @@ func TestRun(t *testing.T) {
 		}
 
 		ctx := context.Background()
-		err := runner.Run(ctx, &domain.Action{
+		_, err := runner.Run(ctx, &domain.Action{
 			Request: tt.Req,
 		})
 
@@ -95,3 +95,175 @@ func setupClient(handler http.HandlerFunc) (*http.Client, func()) {
 	}
 	return c, s.Close
 }
+
+func TestRunAssertions(t *testing.T) {
+	type tc struct {
+		Status     int
+		Body       string
+		Assertions *domain.HTTPAssertions
+		Err        string
+	}
+	var testcases = []tc{
+		{
+			Status: 200,
+			Body:   `{"ok": true, "data": {"count": 2}}`,
+			Assertions: &domain.HTTPAssertions{
+				Headers: []*domain.NameValuePair{
+					{Name: "Content-Type", Value: "application/json"},
+				},
+				Body:        `"ok"`,
+				BodyPattern: `"count":\s*\d+`,
+				JSON: []*domain.NameValuePair{
+					{Name: "$.ok", Value: "true"},
+					{Name: "$.data.count", Value: "2"},
+				},
+			},
+		},
+		{
+			Status: 200,
+			Body:   `{"ok": false}`,
+			Assertions: &domain.HTTPAssertions{
+				JSON: []*domain.NameValuePair{
+					{Name: "$.ok", Value: "true"},
+				},
+			},
+			Err: "200 assertion failed: $.ok false, expected true",
+		},
+		{
+			Status: 200,
+			Body:   `{"id": 9007199254740993, "total": 1000000000000000000000}`,
+			Assertions: &domain.HTTPAssertions{
+				JSON: []*domain.NameValuePair{
+					{Name: "$.id", Value: "9007199254740993"},
+					{Name: "$.total", Value: "1000000000000000000000"},
+				},
+			},
+		},
+		{
+			Status: 202,
+			Assertions: &domain.HTTPAssertions{
+				StatusCodes: []int{200, 204},
+			},
+			Err: "202 assertion failed: status code 202, expected one of [200 204]",
+		},
+		{
+			Status: 404,
+			Assertions: &domain.HTTPAssertions{
+				StatusCodes: []int{404},
+			},
+		},
+		{
+			Status: 500,
+			Assertions: &domain.HTTPAssertions{
+				Body: "ok",
+			},
+			Err: "500 Internal Server Error",
+		},
+		{
+			Status: 200,
+			Assertions: &domain.HTTPAssertions{
+				Headers: []*domain.NameValuePair{
+					{Name: "X-Request-Id"},
+				},
+			},
+			Err: "200 assertion failed: header X-Request-Id is missing",
+		},
+		{
+			Status: 200,
+			Body:   `<html></html>`,
+			Assertions: &domain.HTTPAssertions{
+				BodyPattern: `^\{`,
+			},
+			Err: "200 assertion failed: body does not match \"^\\\\{\"",
+		},
+	}
+	for _, tt := range testcases {
+		client, teardown := setupClient(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(tt.Status)
+			_, _ = w.Write([]byte(tt.Body))
+		})
+		defer teardown()
+		runner := &httpRunner{
+			client: client,
+		}
+
+		_, err := runner.Run(context.Background(), &domain.Action{
+			Request:    &domain.HTTPRequest{URI: "http://127.0.0.1:8000/test"},
+			Assertions: tt.Assertions,
+		})
+
+		actual := ""
+		if err != nil {
+			actual = err.Error()
+		}
+		if actual != tt.Err {
+			t.Errorf("err, got: %q, expected: %q", actual, tt.Err)
+		}
+	}
+}
+
+func TestRunSnapshot(t *testing.T) {
+	type tc struct {
+		Policy   *domain.SnapshotPolicy
+		Expected *domain.HTTPResponse
+	}
+	const body = `{"token":"s3cr3t","items":[1,2,3]}`
+	var testcases = []tc{
+		{
+			Expected: &domain.HTTPResponse{
+				StatusCode: 200,
+				Headers: []*domain.NameValuePair{
+					{Name: "Content-Type", Value: "application/json"},
+				},
+				Body: body,
+				Size: len(body),
+			},
+		},
+		{
+			Policy: &domain.SnapshotPolicy{
+				Headers:   []string{"x-session"},
+				BodyLimit: 20,
+				Redact:    []string{`s3cr3t`, `sid=\w+`},
+			},
+			Expected: &domain.HTTPResponse{
+				StatusCode: 200,
+				Headers: []*domain.NameValuePair{
+					{Name: "X-Session", Value: "***; path=/"},
+				},
+				Body: `{"token":"***","item`,
+				Size: len(body),
+			},
+		},
+		{
+			Policy: &domain.SnapshotPolicy{},
+			Expected: &domain.HTTPResponse{
+				StatusCode: 200,
+				Size:       len(body),
+			},
+		},
+	}
+	for _, tt := range testcases {
+		client, teardown := setupClient(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Session", "sid=abc123; path=/")
+			_, _ = w.Write([]byte(body))
+		})
+		defer teardown()
+		runner := &httpRunner{
+			client: client,
+		}
+
+		res, err := runner.Run(context.Background(), &domain.Action{
+			Request:  &domain.HTTPRequest{URI: "http://127.0.0.1:8000/test"},
+			Snapshot: tt.Policy,
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(res.Response, tt.Expected) {
+			t.Errorf("response, got: %+v, expected: %+v", res.Response, tt.Expected)
+		}
+	}
+}
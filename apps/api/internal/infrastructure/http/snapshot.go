@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+const redacted = "***"
+
+// snapshot captures the response as defined by the policy: selected
+// headers and the body truncated to the limit, with redact pattern
+// matches masked.
+func snapshot(p *domain.SnapshotPolicy, resp *http.Response, body []byte) *domain.HTTPResponse {
+	if p == nil {
+		p = domain.DefaultSnapshotPolicy
+	}
+	patterns := make([]*regexp.Regexp, 0, len(p.Redact))
+	for _, pattern := range p.Redact {
+		if r, err := regexp.Compile(pattern); err == nil {
+			patterns = append(patterns, r)
+		}
+	}
+	s := &domain.HTTPResponse{
+		StatusCode: resp.StatusCode,
+		Size:       len(body),
+	}
+	for _, name := range p.Headers {
+		for _, value := range resp.Header.Values(name) {
+			s.Headers = append(s.Headers, &domain.NameValuePair{
+				Name:  http.CanonicalHeaderKey(name),
+				Value: redact(patterns, value),
+			})
+		}
+	}
+	if p.BodyLimit > 0 && len(body) > 0 {
+		s.Body = domain.TruncateMessage(
+			redact(patterns, string(body)), p.BodyLimit)
+	}
+	return s
+}
+
+func redact(patterns []*regexp.Regexp, s string) string {
+	for _, r := range patterns {
+		s = r.ReplaceAllString(s, redacted)
+	}
+	return s
+}
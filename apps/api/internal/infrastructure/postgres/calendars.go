@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+func (r *sqlRepository) ListCalendars() ([]*domain.CalendarItem, error) {
+	items := make([]*domain.CalendarItem, 0, 10)
+	rows, err := r.selectCalendars.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("failed to close rows: %v", err)
+		}
+	}()
+	for rows.Next() {
+		c := &domain.CalendarItem{}
+		err := rows.Scan(&c.ID, &c.Name, &c.Updated)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *sqlRepository) CreateCalendar(c *domain.Calendar) error {
+	dates, ranges, err := marshalCalendar(c)
+	if err != nil {
+		return err
+	}
+	return checkExec(r.insertCalendar.Exec(
+		c.ID, c.Name, c.Timezone, dates, ranges,
+	))
+}
+
+func (r *sqlRepository) RetrieveCalendar(id string) (*domain.Calendar, error) {
+	c := &domain.Calendar{}
+	var dates, ranges []byte
+	err := r.selectCalendar.QueryRow(id).Scan(
+		&c.ID, &c.Name, &c.Updated, &c.Timezone, &dates, &ranges,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(dates, &c.Dates); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(ranges, &c.Ranges); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *sqlRepository) UpdateCalendar(c *domain.Calendar) error {
+	dates, ranges, err := marshalCalendar(c)
+	if err != nil {
+		return err
+	}
+	return checkExec(r.updateCalendar.Exec(
+		c.ID, c.Updated, c.Name, c.Timezone, dates, ranges,
+	))
+}
+
+func (r *sqlRepository) DeleteCalendar(id string) error {
+	return checkExec(r.deleteCalendar.Exec(id))
+}
+
+func marshalCalendar(c *domain.Calendar) (string, string, error) {
+	dates, err := json.Marshal(c.Dates)
+	if err != nil {
+		return "", "", err
+	}
+	ranges, err := json.Marshal(c.Ranges)
+	if err != nil {
+		return "", "", err
+	}
+	return string(dates), string(ranges), nil
+}
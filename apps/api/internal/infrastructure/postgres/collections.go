@@ -3,8 +3,10 @@ package postgres
 import (
 	"database/sql"
 	"log"
+	"time"
 
 	"github.com/akornatskyy/scheduler/internal/domain"
+	"github.com/lib/pq"
 )
 
 func (r *sqlRepository) ListCollections() ([]*domain.CollectionItem, error) {
@@ -34,14 +36,18 @@ func (r *sqlRepository) ListCollections() ([]*domain.CollectionItem, error) {
 
 func (r *sqlRepository) CreateCollection(c *domain.Collection) error {
 	return checkExec(r.insertCollection.Exec(
-		c.ID, c.Name, c.State,
+		c.ID, c.Name, c.State, c.Timezone, c.Priority, c.MaxRuns,
+		time.Duration(c.Jitter).Milliseconds(), c.PausedUntil,
+		pq.Array(c.Calendars),
 	))
 }
 
 func (r *sqlRepository) RetrieveCollection(id string) (*domain.Collection, error) {
 	c := &domain.Collection{}
+	var jitter int64
 	err := r.selectCollection.QueryRow(id).Scan(
-		&c.ID, &c.Name, &c.Updated, &c.State,
+		&c.ID, &c.Name, &c.Updated, &c.State, &c.Timezone, &c.Priority,
+		&c.MaxRuns, &jitter, &c.PausedUntil, pq.Array(&c.Calendars),
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -49,12 +55,15 @@ func (r *sqlRepository) RetrieveCollection(id string) (*domain.Collection, error
 		}
 		return nil, err
 	}
+	c.Jitter = domain.Duration(time.Duration(jitter) * time.Millisecond)
 	return c, nil
 }
 
 func (r *sqlRepository) UpdateCollection(c *domain.Collection) error {
 	return checkExec(r.updateCollection.Exec(
-		c.ID, c.Updated, c.Name, c.State,
+		c.ID, c.Updated, c.Name, c.State, c.Timezone, c.Priority, c.MaxRuns,
+		time.Duration(c.Jitter).Milliseconds(), c.PausedUntil,
+		pq.Array(c.Calendars),
 	))
 }
 
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+const (
+	// leaderLockID is a key of session advisory lock held by the leader.
+	leaderLockID  = 726854
+	electInterval = 2 * time.Second
+)
+
+type sqlElector struct {
+	db     *sql.DB
+	name   string
+	conn   *sql.Conn
+	leader atomic.Bool
+	done   chan struct{}
+	exited chan struct{}
+}
+
+// NewElector returns an elector that holds a Postgres session advisory
+// lock by the leader. Once the leader session drops, the lock is released
+// and acquired by another instance within the elect interval.
+func NewElector(dsn, name string) domain.Elector {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		log.Fatalf("ERR: %s", err)
+	}
+	db.SetMaxOpenConns(2)
+	// a released session must not be kept in the pool holding the lock
+	db.SetMaxIdleConns(0)
+	return &sqlElector{
+		db:     db,
+		name:   name,
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+}
+
+func (e *sqlElector) Start() {
+	go e.run()
+}
+
+func (e *sqlElector) Stop() {
+	close(e.done)
+	<-e.exited
+	if e.conn != nil {
+		if e.leader.Load() {
+			_, _ = e.conn.ExecContext(
+				context.Background(), "SELECT pg_advisory_unlock($1)", leaderLockID)
+		}
+		e.release()
+	}
+	if err := e.db.Close(); err != nil {
+		log.Printf("WARN: failed to close elector: %v", err)
+	}
+}
+
+func (e *sqlElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+func (e *sqlElector) Leader() (string, error) {
+	var name string
+	err := e.db.QueryRow(`
+		SELECT a.application_name
+		FROM pg_locks l
+		INNER JOIN pg_stat_activity a ON l.pid = a.pid
+		WHERE
+			l.locktype = 'advisory' AND l.granted AND
+			l.classid = 0 AND l.objid = $1 AND l.objsubid = 1`,
+		leaderLockID,
+	).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return name, err
+}
+
+func (e *sqlElector) run() {
+	defer close(e.exited)
+	ticker := time.NewTicker(electInterval)
+	defer ticker.Stop()
+	for {
+		if err := e.elect(); err != nil {
+			log.Printf("WARN: elector: %s", err)
+			if e.leader.Swap(false) {
+				log.Print("lost leadership")
+			}
+			e.release()
+		}
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *sqlElector) elect() error {
+	ctx, cancel := context.WithTimeout(context.Background(), electInterval)
+	defer cancel()
+	if e.conn == nil {
+		conn, err := e.db.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		e.conn = conn
+		_, err = conn.ExecContext(
+			ctx, "SELECT set_config('application_name', $1, false)", e.name)
+		if err != nil {
+			return err
+		}
+	}
+	if e.leader.Load() {
+		// the lock is held as long as the session is alive
+		return e.conn.PingContext(ctx)
+	}
+	var acquired bool
+	err := e.conn.QueryRowContext(
+		ctx, "SELECT pg_try_advisory_lock($1)", leaderLockID,
+	).Scan(&acquired)
+	if err != nil {
+		return err
+	}
+	if acquired {
+		e.leader.Store(true)
+		log.Printf("elected as leader: %s", e.name)
+	}
+	return nil
+}
+
+func (e *sqlElector) release() {
+	if e.conn == nil {
+		return
+	}
+	if err := e.conn.Close(); err != nil {
+		log.Printf("WARN: failed to close elector connection: %v", err)
+	}
+	e.conn = nil
+}
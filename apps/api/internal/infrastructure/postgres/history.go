@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"database/sql"
+	"encoding/json"
 	"log"
 	"time"
 
@@ -21,7 +23,7 @@ func (r *sqlRepository) ListJobHistory(id string) ([]*domain.JobHistory, error)
 	for rows.Next() {
 		j := &domain.JobHistory{}
 		err := rows.Scan(
-			&j.Action, &j.Started, &j.Finished, &j.Status,
+			&j.ID, &j.Action, &j.Scheduled, &j.Started, &j.Finished, &j.Status,
 			&j.RetryCount, &j.Message,
 		)
 		if err != nil {
@@ -35,11 +37,95 @@ func (r *sqlRepository) ListJobHistory(id string) ([]*domain.JobHistory, error)
 	return items, nil
 }
 
+func (r *sqlRepository) RetrieveJobHistory(jobID, id string) (*domain.JobHistory, error) {
+	j := &domain.JobHistory{JobID: jobID}
+	var response []byte
+	err := r.selectJobHistoryItem.QueryRow(jobID, id).Scan(
+		&j.ID, &j.Action, &j.Scheduled, &j.Started, &j.Finished, &j.Status,
+		&j.RetryCount, &j.Message, &response,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	if response != nil {
+		j.Response = &domain.HTTPResponse{}
+		if err := json.Unmarshal(response, j.Response); err != nil {
+			return nil, err
+		}
+	}
+	j.Attempts, err = r.listJobAttempts(id)
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (r *sqlRepository) listJobAttempts(historyID string) ([]*domain.JobAttempt, error) {
+	items := make([]*domain.JobAttempt, 0, 4)
+	rows, err := r.selectJobAttempts.Query(historyID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("WARN: failed to close rows: %v", err)
+		}
+	}()
+	for rows.Next() {
+		a := &domain.JobAttempt{}
+		err := rows.Scan(
+			&a.Started, &a.Finished, &a.Code, &a.Message, &a.Delay,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 func (r *sqlRepository) AddJobHistory(jh *domain.JobHistory) error {
-	return checkExec(r.insertJobHistory.Exec(
-		domain.NewID(), jh.JobID, jh.Action, jh.Started, jh.Finished,
-		jh.Status, jh.RetryCount, jh.Message,
+	var response interface{}
+	if jh.Response != nil {
+		b, err := json.Marshal(jh.Response)
+		if err != nil {
+			return err
+		}
+		response = b
+	}
+	if jh.ID == "" {
+		jh.ID = domain.NewID()
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	err = checkExec(tx.Stmt(r.insertJobHistory).Exec(
+		jh.ID, jh.JobID, jh.Action, jh.Started, jh.Finished,
+		jh.Status, jh.RetryCount, jh.Message, response, jh.Scheduled,
 	))
+	for i, a := range jh.Attempts {
+		if err != nil {
+			break
+		}
+		err = checkExec(tx.Stmt(r.insertJobAttempt).Exec(
+			jh.ID, i+1, a.Started, a.Finished, a.Code, a.Message,
+			int64(a.Delay),
+		))
+	}
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.Printf("WARN: failed to rollback: %v", rollbackErr)
+		}
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *sqlRepository) DeleteJobHistory(id string, before time.Time) error {
@@ -38,27 +38,105 @@ func (r *sqlRepository) ListJobs(collectionID string, fields []string) ([]*domai
 }
 
 func (r *sqlRepository) CreateJob(j *domain.JobDefinition) error {
+	misfire, err := marshalNullable(j.Misfire)
+	if err != nil {
+		return err
+	}
+	concurrency, err := marshalNullable(j.Concurrency)
+	if err != nil {
+		return err
+	}
 	action, err := json.Marshal(j.Action)
 	if err != nil {
 		return err
 	}
+	triggers, err := marshalItems(j.Triggers)
+	if err != nil {
+		return err
+	}
 	return checkExec(r.insertJob.Exec(
-		j.ID, j.Name, j.CollectionID, j.State, j.Schedule, action,
+		j.ID, j.Name, j.CollectionID, j.State, j.Schedule,
+		pq.Array(j.Schedules), j.Timezone, j.Priority,
+		time.Duration(j.Jitter).Milliseconds(), j.NotBefore, j.NotAfter,
+		pq.Array(j.Calendars), triggers, misfire, concurrency, action,
 	))
 }
 
 func (r *sqlRepository) RetrieveJob(id string) (*domain.JobDefinition, error) {
-	j := &domain.JobDefinition{}
-	var s string
-	err := r.selectJob.QueryRow(id).Scan(
-		&j.ID, &j.Name, &j.Updated, &j.CollectionID, &j.State, &j.Schedule, &s,
-	)
+	j, err := scanJob(r.selectJob.QueryRow(id).Scan)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, domain.ErrNotFound
 		}
 		return nil, err
 	}
+	return j, nil
+}
+
+func (r *sqlRepository) ListEnabledJobs(collectionID string) ([]*domain.JobDefinition, error) {
+	items := make([]*domain.JobDefinition, 0)
+	rows, err := r.selectEnabledJobs.Query(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("WARN: failed to close rows: %v", err)
+		}
+	}()
+	for rows.Next() {
+		c := &domain.Collection{}
+		var jitter int64
+		j, err := scanJob(rows.Scan, &c.Timezone, &jitter, &c.PausedUntil)
+		if err != nil {
+			return nil, err
+		}
+		c.Jitter = domain.Duration(time.Duration(jitter) * time.Millisecond)
+		j.Inherit(c)
+		items = append(items, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// scanJob scans the job columns as selected by selectJob followed by the
+// extra ones.
+func scanJob(
+	scan func(dest ...interface{}) error, extra ...interface{},
+) (*domain.JobDefinition, error) {
+	j := &domain.JobDefinition{}
+	var s string
+	var triggers, misfire, concurrency []byte
+	var jitter int64
+	dest := []interface{}{
+		&j.ID, &j.Name, &j.Updated, &j.CollectionID, &j.State, &j.Schedule,
+		pq.Array(&j.Schedules), &j.Timezone, &j.Priority, &jitter,
+		&j.NotBefore, &j.NotAfter, pq.Array(&j.Calendars), &triggers,
+		&misfire, &concurrency, &s,
+	}
+	if err := scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	j.Jitter = domain.Duration(time.Duration(jitter) * time.Millisecond)
+	if triggers != nil {
+		if err := json.Unmarshal(triggers, &j.Triggers); err != nil {
+			return nil, err
+		}
+	}
+	if misfire != nil {
+		j.Misfire = &domain.MisfirePolicy{}
+		if err := json.Unmarshal(misfire, j.Misfire); err != nil {
+			return nil, err
+		}
+	}
+	if concurrency != nil {
+		j.Concurrency = &domain.ConcurrencyPolicy{}
+		if err := json.Unmarshal(concurrency, j.Concurrency); err != nil {
+			return nil, err
+		}
+	}
 	j.Action = &domain.Action{}
 	if err := json.Unmarshal([]byte(s), j.Action); err != nil {
 		return nil, err
@@ -67,23 +145,95 @@ func (r *sqlRepository) RetrieveJob(id string) (*domain.JobDefinition, error) {
 }
 
 func (r *sqlRepository) UpdateJob(j *domain.JobDefinition) error {
+	misfire, err := marshalNullable(j.Misfire)
+	if err != nil {
+		return err
+	}
+	concurrency, err := marshalNullable(j.Concurrency)
+	if err != nil {
+		return err
+	}
 	action, err := json.Marshal(j.Action)
 	if err != nil {
 		return err
 	}
+	triggers, err := marshalItems(j.Triggers)
+	if err != nil {
+		return err
+	}
 	return checkExec(r.updateJob.Exec(
-		j.ID, j.Updated, j.Name, j.CollectionID, j.State, j.Schedule, action,
+		j.ID, j.Updated, j.Name, j.CollectionID, j.State, j.Schedule,
+		pq.Array(j.Schedules), j.Timezone, j.Priority,
+		time.Duration(j.Jitter).Milliseconds(), j.NotBefore, j.NotAfter,
+		pq.Array(j.Calendars), triggers, misfire, concurrency, action,
 	))
 }
 
+// marshalNullable returns JSON of v or nil for a NULL column value.
+func marshalNullable[T any](v *T) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// marshalItems returns JSON of items or nil for a NULL column value if
+// there are none.
+func marshalItems[T any](items []T) (interface{}, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(items)
+}
+
 func (r *sqlRepository) DeleteJob(id string) error {
 	return checkExec(r.deleteJob.Exec(id))
 }
 
+func (r *sqlRepository) ListJobTriggers() (map[string][]*domain.JobTrigger, error) {
+	return queryJobTriggers(r.selectJobTriggers)
+}
+
+func (r *sqlRepository) ListDependentJobs(upstreamID string) (map[string][]*domain.JobTrigger, error) {
+	return queryJobTriggers(r.selectDependentJobs, upstreamID)
+}
+
+func queryJobTriggers(
+	stmt *sql.Stmt, args ...interface{},
+) (map[string][]*domain.JobTrigger, error) {
+	items := make(map[string][]*domain.JobTrigger)
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("WARN: failed to close rows: %v", err)
+		}
+	}()
+	for rows.Next() {
+		var id string
+		var triggers []byte
+		if err := rows.Scan(&id, &triggers); err != nil {
+			return nil, err
+		}
+		var t []*domain.JobTrigger
+		if err := json.Unmarshal(triggers, &t); err != nil {
+			return nil, err
+		}
+		items[id] = t
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 func (r *sqlRepository) RetrieveJobStatus(id string) (*domain.JobStatus, error) {
 	j := &domain.JobStatus{}
 	err := r.selectJobStatus.QueryRow(id).Scan(
 		&j.Updated, &j.Running, &j.RunCount, &j.ErrorCount, &j.LastRun,
+		&j.Generation, &j.Pending,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -122,6 +272,42 @@ func (r *sqlRepository) ResetJobStatus(id string) error {
 	return checkExec(r.resetJobStatus.Exec(id, domain.NewID()))
 }
 
-func (r *sqlRepository) AcquireJob(id string, deadline time.Duration) error {
-	return checkExec(r.updateJobStatus.Exec(id, deadline.String()))
+func (r *sqlRepository) AcquireJob(
+	id string, deadline time.Duration, p *domain.ConcurrencyPolicy,
+) (int, error) {
+	var generation int
+	err := r.updateJobStatus.QueryRow(
+		id, deadline.String(), p.Replace(), p.MaxRuns(),
+	).Scan(&generation)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, domain.ErrConflict
+		}
+		return 0, err
+	}
+	return generation, nil
+}
+
+func (r *sqlRepository) EnqueueJobRun(id string, scheduled time.Time) error {
+	err := checkExec(r.insertJobRun.Exec(id, scheduled))
+	if err == domain.ErrNotFound {
+		return domain.ErrDuplicateRun
+	}
+	return err
+}
+
+func (r *sqlRepository) ClaimJobRun() (*domain.JobRun, error) {
+	run := &domain.JobRun{}
+	err := r.claimJobRun.QueryRow().Scan(&run.JobID, &run.Scheduled)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return run, nil
+}
+
+func (r *sqlRepository) FinishJobRun(run *domain.JobRun) error {
+	return checkExec(r.finishJobRun.Exec(run.JobID, run.Scheduled))
 }
@@ -174,4 +174,107 @@ var migrations = []string{
 		CONSTRAINT variable_collection_fk FOREIGN KEY (collection_id)
 			REFERENCES collection(id)
 	)`,
+	`
+	ALTER TABLE job_history ADD COLUMN response JSON`,
+	`
+	CREATE TABLE job_attempt (
+		history_id VARCHAR(36) NOT NULL,
+		seq INT NOT NULL,
+		started TIMESTAMPTZ NOT NULL,
+		finished TIMESTAMPTZ NOT NULL,
+		code INT NOT NULL,
+		message VARCHAR(1024),
+		delay BIGINT NOT NULL,
+
+		PRIMARY KEY (history_id, seq),
+		CONSTRAINT job_attempt_job_history_fk FOREIGN KEY (history_id)
+			REFERENCES job_history(id) ON DELETE CASCADE
+	)`,
+	`
+	ALTER TABLE collection ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT ''`,
+	`
+	ALTER TABLE job ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT ''`,
+	`
+	ALTER TABLE job ADD COLUMN misfire JSON`,
+	`
+	ALTER TABLE job ADD COLUMN concurrency JSON`,
+	`
+	ALTER TABLE job_status
+		ADD COLUMN running_count INT NOT NULL DEFAULT 0,
+		ADD COLUMN generation INT NOT NULL DEFAULT 0;
+
+	UPDATE job_status SET running_count = 1 WHERE running`,
+	`
+	INSERT INTO job_history_status VALUES
+	(3, 'skipped')`,
+	`
+	CREATE TABLE job_run (
+		job_id VARCHAR(36) NOT NULL,
+		scheduled TIMESTAMPTZ NOT NULL,
+
+		PRIMARY KEY (job_id, scheduled),
+		CONSTRAINT job_run_job_fk FOREIGN KEY (job_id)
+			REFERENCES job(id) ON DELETE CASCADE
+	)`,
+	`
+	ALTER TABLE job_history ADD COLUMN scheduled TIMESTAMPTZ`,
+	`
+	ALTER TABLE job_run
+		ADD COLUMN state_id INT NOT NULL DEFAULT 3,
+		ADD COLUMN enqueued TIMESTAMPTZ NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+		ADD COLUMN claimed TIMESTAMPTZ;
+
+	CREATE INDEX job_run_queue_idx ON job_run (scheduled) WHERE state_id < 3`,
+	`
+	ALTER TABLE collection
+		ADD COLUMN priority INT NOT NULL DEFAULT 0,
+		ADD COLUMN max_runs INT NOT NULL DEFAULT 0`,
+	`
+	ALTER TABLE job ADD COLUMN priority INT`,
+	`
+	ALTER TABLE collection ADD COLUMN jitter_ms INT NOT NULL DEFAULT 0;
+	ALTER TABLE job ADD COLUMN jitter_ms INT NOT NULL DEFAULT 0`,
+	`
+	ALTER TABLE collection ADD COLUMN paused_until TIMESTAMPTZ;
+	ALTER TABLE job
+		ADD COLUMN not_before TIMESTAMPTZ,
+		ADD COLUMN not_after TIMESTAMPTZ`,
+	`
+	CREATE TABLE calendar (
+		id VARCHAR(36) NOT NULL,
+		name VARCHAR(64) NOT NULL UNIQUE,
+		updated TIMESTAMPTZ NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC'),
+		timezone VARCHAR(64) NOT NULL DEFAULT '',
+		dates JSON NOT NULL,
+		ranges JSON NOT NULL,
+
+		PRIMARY KEY (id)
+	);
+
+	ALTER TABLE collection
+		ADD COLUMN calendars VARCHAR(36)[] NOT NULL DEFAULT '{}';
+	ALTER TABLE job ADD COLUMN calendars VARCHAR(36)[] NOT NULL DEFAULT '{}'`,
+	`
+	CREATE OR REPLACE FUNCTION calendar_delete_check() RETURNS trigger AS $$
+	BEGIN
+		IF EXISTS (SELECT 1 FROM collection WHERE OLD.id = ANY(calendars)) OR
+			EXISTS (SELECT 1 FROM job WHERE OLD.id = ANY(calendars)) THEN
+			RAISE foreign_key_violation USING
+				MESSAGE = 'calendar ' || OLD.id || ' is in use';
+		END IF;
+		RETURN OLD;
+	END;
+	$$ LANGUAGE plpgsql;
+
+	CREATE TRIGGER calendar_delete_check BEFORE DELETE ON calendar
+	FOR EACH ROW EXECUTE PROCEDURE calendar_delete_check()`,
+	`
+	ALTER TABLE job ALTER COLUMN schedule TYPE VARCHAR(512)`,
+	`
+	ALTER TABLE job ADD COLUMN schedules VARCHAR(512)[] NOT NULL DEFAULT '{}'`,
+	`
+	ALTER TABLE job ADD COLUMN triggers JSON;
+
+	CREATE INDEX job_triggers_idx ON job
+	USING GIN ((triggers::jsonb) jsonb_path_ops)`,
 }
@@ -31,20 +31,37 @@ type sqlRepository struct {
 	updateVariable           *sql.Stmt
 	deleteVariable           *sql.Stmt
 
-	selectJobs         *sql.Stmt
-	insertJob          *sql.Stmt
-	selectJob          *sql.Stmt
-	updateJob          *sql.Stmt
-	deleteJob          *sql.Stmt
-	selectLeftOverJobs *sql.Stmt
+	selectCalendars *sql.Stmt
+	insertCalendar  *sql.Stmt
+	selectCalendar  *sql.Stmt
+	updateCalendar  *sql.Stmt
+	deleteCalendar  *sql.Stmt
+
+	selectJobs          *sql.Stmt
+	insertJob           *sql.Stmt
+	selectJob           *sql.Stmt
+	updateJob           *sql.Stmt
+	deleteJob           *sql.Stmt
+	selectEnabledJobs   *sql.Stmt
+	selectJobTriggers   *sql.Stmt
+	selectDependentJobs *sql.Stmt
+	selectLeftOverJobs  *sql.Stmt
 
 	selectJobStatus *sql.Stmt
 	resetJobStatus  *sql.Stmt
 	updateJobStatus *sql.Stmt
 
-	selectJobHistory *sql.Stmt
-	insertJobHistory *sql.Stmt
-	deleteJobHistory *sql.Stmt
+	selectJobHistory     *sql.Stmt
+	selectJobHistoryItem *sql.Stmt
+	insertJobHistory     *sql.Stmt
+	deleteJobHistory     *sql.Stmt
+
+	selectJobAttempts *sql.Stmt
+	insertJobAttempt  *sql.Stmt
+
+	insertJobRun *sql.Stmt
+	claimJobRun  *sql.Stmt
+	finishJobRun *sql.Stmt
 }
 
 // NewRepository returns postgres implementation of domain.Repository
@@ -66,15 +83,23 @@ func NewRepository(dsn string) domain.Repository {
 			FROM collection
 			ORDER BY name`),
 		insertCollection: sqlx.MustPrepare(db, `
-			INSERT INTO collection (id, name, state_id)
-			VALUES ($1, $2, $3)`),
+			INSERT INTO collection (
+				id, name, state_id, timezone, priority, max_runs, jitter_ms,
+				paused_until, calendars)
+			VALUES (
+				$1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9::VARCHAR[], '{}'))`),
 		selectCollection: sqlx.MustPrepare(db, `
-			SELECT id, name, updated, state_id
+			SELECT
+				id, name, updated, state_id, timezone, priority, max_runs,
+				jitter_ms, paused_until, calendars
 			FROM collection
 			WHERE id = $1`),
 		updateCollection: sqlx.MustPrepare(db, `
 			UPDATE collection
-			SET name=$3, updated=now() at time zone 'utc', state_id = $4
+			SET
+				name=$3, updated=now() at time zone 'utc', state_id = $4,
+				timezone=$5, priority=$6, max_runs=$7, jitter_ms=$8,
+				paused_until=$9, calendars=COALESCE($10::VARCHAR[], '{}')
 			WHERE id=$1 AND updated=$2`),
 		deleteCollection: sqlx.MustPrepare(db, `
 			DELETE FROM collection WHERE id = $1`),
@@ -106,6 +131,26 @@ func NewRepository(dsn string) domain.Repository {
 		deleteVariable: sqlx.MustPrepare(db, `
 			DELETE FROM variable WHERE id = $1`),
 
+		selectCalendars: sqlx.MustPrepare(db, `
+			SELECT id, name, updated
+			FROM calendar
+			ORDER BY name`),
+		insertCalendar: sqlx.MustPrepare(db, `
+			INSERT INTO calendar (id, name, timezone, dates, ranges)
+			VALUES ($1, $2, $3, $4, $5)`),
+		selectCalendar: sqlx.MustPrepare(db, `
+			SELECT id, name, updated, timezone, dates, ranges
+			FROM calendar
+			WHERE id = $1`),
+		updateCalendar: sqlx.MustPrepare(db, `
+			UPDATE calendar
+			SET
+				name=$3, updated=now() at time zone 'utc', timezone=$4,
+				dates=$5, ranges=$6
+			WHERE id = $1 AND updated = $2`),
+		deleteCalendar: sqlx.MustPrepare(db, `
+			DELETE FROM calendar WHERE id = $1`),
+
 		selectJobs: sqlx.MustPrepare(db, `
 			SELECT
 				j.id, collection_id, name, state_id, schedule,
@@ -121,6 +166,7 @@ func NewRepository(dsn string) domain.Repository {
 							FROM job_history jh
 							WHERE jh.job_id = j.id
 								AND started > (now() at time zone 'utc' - '1d'::interval)
+								AND jh.status_id <> 3 -- skipped
 							ORDER BY jh.finished DESC
 							LIMIT 1
 						), 1) -- ready
@@ -136,6 +182,7 @@ func NewRepository(dsn string) domain.Repository {
 					FROM job_history jh
 					WHERE jh.job_id = j.id
 						AND started > (now() at time zone 'utc' - '1d'::interval)
+						AND status_id <> 3 -- skipped
 				)
 				END AS error_rate
 			FROM job j
@@ -146,18 +193,54 @@ func NewRepository(dsn string) domain.Repository {
 				INSERT INTO job_status (id)
 				VALUES ($1)
 			)
-			INSERT INTO job (id, name, collection_id, state_id, schedule, action)
-			VALUES ($1, $2, $3, $4, $5, $6)`),
+			INSERT INTO job (
+				id, name, collection_id, state_id, schedule, schedules,
+				timezone, priority, jitter_ms, not_before, not_after, calendars,
+				triggers, misfire, concurrency, action)
+			VALUES (
+				$1, $2, $3, $4, $5, COALESCE($6::VARCHAR[], '{}'), $7, $8, $9,
+				$10, $11, COALESCE($12::VARCHAR[], '{}'), $13, $14, $15, $16)`),
 		selectJob: sqlx.MustPrepare(db, `
-			SELECT id, name, updated, collection_id, state_id, schedule, action
+			SELECT
+				id, name, updated, collection_id, state_id, schedule, schedules,
+				timezone, priority, jitter_ms, not_before, not_after, calendars,
+				triggers, misfire, concurrency, action
 			FROM job
 			WHERE id = $1`),
 		updateJob: sqlx.MustPrepare(db, `
 			UPDATE job j
 			SET
 				name=$3, updated=now() at time zone 'utc', collection_id=$4,
-				state_id=$5, schedule=$6, action=$7
+				state_id=$5, schedule=$6, schedules=COALESCE($7::VARCHAR[], '{}'),
+				timezone=$8, priority=$9, jitter_ms=$10, not_before=$11,
+				not_after=$12, calendars=COALESCE($13::VARCHAR[], '{}'),
+				triggers=$14, misfire=$15, concurrency=$16, action=$17
 			WHERE j.id = $1 AND j.updated = $2`),
+		selectEnabledJobs: sqlx.MustPrepare(db, `
+			SELECT
+				j.id, j.name, j.updated, j.collection_id, j.state_id, j.schedule,
+				j.schedules, j.timezone, j.priority, j.jitter_ms, j.not_before,
+				j.not_after, j.calendars, j.triggers, j.misfire, j.concurrency,
+				j.action, c.timezone, c.jitter_ms, c.paused_until
+			FROM job j
+			INNER JOIN collection c ON j.collection_id = c.id
+			WHERE
+				j.state_id = 1 /* enabled */ AND
+				c.state_id = 1 /* enabled */ AND
+				($1 = '' OR j.collection_id = $1)`),
+		selectJobTriggers: sqlx.MustPrepare(db, `
+			SELECT id, triggers
+			FROM job
+			WHERE triggers IS NOT NULL`),
+		selectDependentJobs: sqlx.MustPrepare(db, `
+			SELECT j.id, j.triggers
+			FROM job j
+			INNER JOIN collection c ON j.collection_id = c.id
+			WHERE
+				j.triggers::jsonb @> jsonb_build_array(
+					jsonb_build_object('jobId', $1::text)) AND
+				j.state_id = 1 /* enabled */ AND
+				c.state_id = 1 /* enabled */`),
 		deleteJob: sqlx.MustPrepare(db, `
 			WITH x AS (
 				DELETE FROM job_status
@@ -175,8 +258,14 @@ func NewRepository(dsn string) domain.Repository {
 								(j.action->'retryPolicy'->>'deadline')::interval`),
 
 		selectJobStatus: sqlx.MustPrepare(db, `
-			SELECT updated, running, run_count, error_count, last_run
-			FROM job_status
+			SELECT
+				updated, running, run_count, error_count, last_run, generation,
+				(
+					SELECT count(*)
+					FROM job_run r
+					WHERE r.job_id = js.id AND r.state_id < 3 /* done */
+				) AS pending
+			FROM job_status js
 			WHERE id = $1`),
 		resetJobStatus: sqlx.MustPrepare(db, `
 			WITH x AS (
@@ -195,6 +284,7 @@ func NewRepository(dsn string) domain.Repository {
 				SET
 					updated=x.finished,
 					running=false,
+					running_count=0,
 					run_count=run_count+1,
 					error_count=error_count+1
 				FROM x
@@ -208,34 +298,122 @@ func NewRepository(dsn string) domain.Repository {
 			FROM x`),
 		updateJobStatus: sqlx.MustPrepare(db, `
 			UPDATE job_status
-			SET updated=now() at time zone 'utc', running=true
+			SET
+				updated=now() at time zone 'utc', running=true,
+				running_count=CASE
+					WHEN age(now() at time zone 'utc', updated) > $2 THEN 1
+					ELSE running_count + 1
+				END,
+				generation=generation + CASE WHEN $3 THEN 1 ELSE 0 END
 			WHERE id = $1 AND (
 				running = false OR
-				age(now() at time zone 'utc', updated) > $2
-			)`),
+				age(now() at time zone 'utc', updated) > $2 OR
+				$3 OR running_count < $4
+			)
+			RETURNING generation`),
 
 		selectJobHistory: sqlx.MustPrepare(db, `
-			SELECT action, started, finished, status_id, retry_count, message
+			SELECT
+				id, action, scheduled, started, finished, status_id, retry_count,
+				message
 			FROM job_history j
 			WHERE job_id = $1
 			ORDER BY started DESC
 			LIMIT 100`),
+		selectJobHistoryItem: sqlx.MustPrepare(db, `
+			SELECT
+				id, action, scheduled, started, finished, status_id, retry_count,
+				message, response
+			FROM job_history j
+			WHERE job_id = $1 AND id = $2`),
 		insertJobHistory: sqlx.MustPrepare(db, `
 			WITH x AS (
 				UPDATE job_status
 				SET
-					updated=now() at time zone 'utc', running=false,
+					updated=now() at time zone 'utc',
+					running=running_count > 1,
+					running_count=GREATEST(running_count - 1, 0),
 					run_count=run_count+1, last_run=$4,
 					error_count = error_count + CASE WHEN $6=1 /* ok */ THEN 0 ELSE 1 END
 				WHERE
-					id = $2
+					id = $2 AND $6 <> 3 /* skipped */
 			)
 			INSERT INTO job_history
-			(id, job_id, action, started, finished, status_id, retry_count, message)
+			(
+				id, job_id, action, started, finished, status_id, retry_count,
+				message, response, scheduled
+			)
 			VALUES
-			($1, $2, $3, $4, $5, $6, $7, $8)`),
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`),
 		deleteJobHistory: sqlx.MustPrepare(db, `
 			DELETE FROM job_history WHERE job_id = $1 AND started < $2`),
+
+		insertJobRun: sqlx.MustPrepare(db, `
+			WITH x AS (
+				DELETE FROM job_run
+				WHERE
+					job_id = $1 AND state_id = 3 /* done */ AND
+					scheduled < (now() at time zone 'utc' - '7d'::interval)
+			)
+			INSERT INTO job_run (job_id, scheduled, state_id)
+			VALUES ($1, $2, 1 /* queued */)
+			ON CONFLICT DO NOTHING`),
+		claimJobRun: sqlx.MustPrepare(db, `
+			UPDATE job_run
+			SET state_id=2 /* running */, claimed=now() at time zone 'utc'
+			WHERE (job_id, scheduled) = (
+				SELECT r.job_id, r.scheduled
+				FROM job_run r
+				INNER JOIN job j ON r.job_id = j.id
+				INNER JOIN collection c ON j.collection_id = c.id
+				WHERE (
+					r.state_id = 1 /* queued */ AND
+					-- a triggered run might be delayed
+					r.scheduled <= now() OR
+					r.state_id = 2 /* running */ AND
+					-- claimed by a worker that is gone
+					age(now() at time zone 'utc', r.claimed) > COALESCE(
+						(j.action->'retryPolicy'->>'deadline')::interval,
+						'20s'::interval
+					) + '1m'::interval
+				) AND (
+					-- might be exceeded slightly by workers claiming at once
+					c.max_runs = 0 OR c.max_runs > (
+						SELECT count(*)
+						FROM job_run cr
+						INNER JOIN job cj ON cr.job_id = cj.id
+						WHERE
+							cj.collection_id = c.id AND
+							cr.state_id = 2 /* running */ AND
+							-- not yet due to be reclaimed
+							age(now() at time zone 'utc', cr.claimed) <= COALESCE(
+								(cj.action->'retryPolicy'->>'deadline')::interval,
+								'20s'::interval
+							) + '1m'::interval
+					)
+				)
+				ORDER BY
+					COALESCE(j.priority, c.priority) DESC,
+					r.scheduled
+				LIMIT 1
+				FOR UPDATE OF r SKIP LOCKED
+			)
+			RETURNING job_id, scheduled`),
+		finishJobRun: sqlx.MustPrepare(db, `
+			UPDATE job_run
+			SET state_id=3 /* done */
+			WHERE job_id = $1 AND scheduled = $2`),
+
+		selectJobAttempts: sqlx.MustPrepare(db, `
+			SELECT started, finished, code, message, delay
+			FROM job_attempt
+			WHERE history_id = $1
+			ORDER BY seq`),
+		insertJobAttempt: sqlx.MustPrepare(db, `
+			INSERT INTO job_attempt
+			(history_id, seq, started, finished, code, message, delay)
+			VALUES
+			($1, $2, $3, $4, $5, $6, $7)`),
 	}
 }
 
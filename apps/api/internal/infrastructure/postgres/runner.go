@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+const (
+	maxOpenConns    = 2
+	connMaxIdleTime = 5 * time.Minute
+	// maxPools limits a number of open databases, the least recently used
+	// idle one is closed to open another.
+	maxPools = 8
+)
+
+type sqlRunner struct {
+	driver string
+	mu     sync.Mutex
+	pools  map[string]*pool
+}
+
+type pool struct {
+	db    *sql.DB
+	users int
+	used  time.Time
+}
+
+// NewRunner returns a runner that executes SQL statements against
+// a database specified by the statement data source name.
+func NewRunner() domain.Runner {
+	return &sqlRunner{
+		driver: "postgres",
+		pools:  make(map[string]*pool),
+	}
+}
+
+func (runner *sqlRunner) Run(ctx context.Context, a *domain.Action) (*domain.RunResult, error) {
+	st := a.Statement
+	db, release, err := runner.acquire(st.DSN)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	started := time.Now()
+	var msg string
+	if st.MaxRows > 0 {
+		msg, err = query(ctx, db, st.Query, st.MaxRows)
+	} else {
+		msg, err = exec(ctx, db, st.Query)
+	}
+	elapsed := time.Since(started).Round(time.Millisecond)
+	if err != nil {
+		log.Printf("SQL - %s", err)
+		return nil, err
+	}
+	log.Printf("SQL - %s %s", strings.SplitN(msg, "\n", 2)[0], elapsed)
+	return &domain.RunResult{
+		Message: msg,
+	}, nil
+}
+
+// Close closes the open databases.
+func (runner *sqlRunner) Close() error {
+	defer runner.mu.Unlock()
+	runner.mu.Lock()
+	for dsn, p := range runner.pools {
+		if err := p.db.Close(); err != nil {
+			log.Printf("WARN: failed to close database: %v", err)
+		}
+		delete(runner.pools, dsn)
+	}
+	return nil
+}
+
+// acquire returns the database of the data source name, it is not closed
+// until released.
+func (runner *sqlRunner) acquire(dsn string) (*sql.DB, func(), error) {
+	defer runner.mu.Unlock()
+	runner.mu.Lock()
+	p := runner.pools[dsn]
+	if p == nil {
+		runner.evict()
+		db, err := sql.Open(runner.driver, dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		db.SetMaxOpenConns(maxOpenConns)
+		db.SetConnMaxIdleTime(connMaxIdleTime)
+		p = &pool{db: db}
+		runner.pools[dsn] = p
+	}
+	p.users++
+	p.used = time.Now()
+	return p.db, func() {
+		defer runner.mu.Unlock()
+		runner.mu.Lock()
+		p.users--
+		p.used = time.Now()
+	}, nil
+}
+
+// evict closes the least recently used idle databases over the limit, the
+// limit is exceeded while all of them are in use.
+func (runner *sqlRunner) evict() {
+	for len(runner.pools) >= maxPools {
+		var lru string
+		var oldest *pool
+		for dsn, p := range runner.pools {
+			if p.users == 0 && (oldest == nil || p.used.Before(oldest.used)) {
+				lru, oldest = dsn, p
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		delete(runner.pools, lru)
+		if err := oldest.db.Close(); err != nil {
+			log.Printf("WARN: failed to close database: %v", err)
+		}
+	}
+}
+
+func exec(ctx context.Context, db *sql.DB, q string) (string, error) {
+	res, err := db.ExecContext(ctx, q)
+	if err != nil {
+		return "", err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d rows affected", n), nil
+}
+
+func query(ctx context.Context, db *sql.DB, q string, maxRows int) (string, error) {
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("WARN: failed to close rows: %v", err)
+		}
+	}()
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	values := make([]interface{}, len(columns))
+	for i := range values {
+		values[i] = new(sql.RawBytes)
+	}
+	var b strings.Builder
+	b.WriteString(strings.Join(columns, "\t"))
+	n := 0
+	for rows.Next() {
+		n++
+		if n > maxRows {
+			continue
+		}
+		if err := rows.Scan(values...); err != nil {
+			return "", err
+		}
+		b.WriteByte('\n')
+		for i, v := range values {
+			if i > 0 {
+				b.WriteByte('\t')
+			}
+			raw := *v.(*sql.RawBytes)
+			if raw == nil {
+				b.WriteString("NULL")
+			} else {
+				b.Write(raw)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d rows\n%s", n, b.String()), nil
+}
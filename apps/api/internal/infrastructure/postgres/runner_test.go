@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+// fakeDriver answers any statement with 3 affected rows and any query with
+// 3 rows of id and name columns.
+type fakeDriver struct {
+	mu      sync.Mutex
+	queries []string
+	closed  int
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+type fakeRows struct {
+	n int
+}
+
+var testDriver = &fakeDriver{}
+
+func init() {
+	sql.Register("runnertest", testDriver)
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+func (d *fakeDriver) record(q string) {
+	d.mu.Lock()
+	d.queries = append(d.queries, q)
+	d.mu.Unlock()
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) Close() error {
+	c.d.mu.Lock()
+	c.d.closed++
+	c.d.mu.Unlock()
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeConn) ExecContext(
+	ctx context.Context, query string, args []driver.NamedValue,
+) (driver.Result, error) {
+	c.d.record(query)
+	return driver.RowsAffected(3), nil
+}
+
+func (c *fakeConn) QueryContext(
+	ctx context.Context, query string, args []driver.NamedValue,
+) (driver.Rows, error) {
+	c.d.record(query)
+	return &fakeRows{}, nil
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"id", "name"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.n == 3 {
+		return io.EOF
+	}
+	r.n++
+	dest[0] = int64(r.n)
+	if r.n == 2 {
+		dest[1] = nil
+	} else {
+		dest[1] = fmt.Sprintf("job-%d", r.n)
+	}
+	return nil
+}
+
+func newTestRunner() *sqlRunner {
+	return &sqlRunner{
+		driver: "runnertest",
+		pools:  make(map[string]*pool),
+	}
+}
+
+func TestRun(t *testing.T) {
+	var testcases = []struct {
+		query   string
+		maxRows int
+		message string
+	}{
+		{"VACUUM audit", 0, "3 rows affected"},
+		{"SELECT id, name FROM job", 5,
+			"3 rows\nid\tname\n1\tjob-1\n2\tNULL\n3\tjob-3"},
+		{"SELECT id, name FROM job", 2,
+			"3 rows\nid\tname\n1\tjob-1\n2\tNULL"},
+	}
+	runner := newTestRunner()
+	defer runner.Close()
+	for _, tt := range testcases {
+		res, err := runner.Run(context.Background(), &domain.Action{
+			Statement: &domain.SQLStatement{
+				DSN:     "test",
+				Query:   tt.query,
+				MaxRows: tt.maxRows,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Message != tt.message {
+			t.Errorf("%s: message, got: %q, expected: %q",
+				tt.query, res.Message, tt.message)
+		}
+		if q := testDriver.queries[len(testDriver.queries)-1]; q != tt.query {
+			t.Errorf("query, got: %q, expected: %q", q, tt.query)
+		}
+	}
+}
+
+func TestRunEvictsPools(t *testing.T) {
+	runner := newTestRunner()
+	testDriver.mu.Lock()
+	closed := testDriver.closed
+	testDriver.mu.Unlock()
+
+	for i := 0; i < maxPools+3; i++ {
+		_, err := runner.Run(context.Background(), &domain.Action{
+			Statement: &domain.SQLStatement{
+				DSN:   fmt.Sprintf("test-%d", i),
+				Query: "VACUUM audit",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := len(runner.pools); n != maxPools {
+		t.Errorf("pools, got: %d, expected: %d", n, maxPools)
+	}
+	if _, ok := runner.pools["test-0"]; ok {
+		t.Error("the least recently used pool is not evicted")
+	}
+	runner.Close()
+	testDriver.mu.Lock()
+	defer testDriver.mu.Unlock()
+	if n := testDriver.closed - closed; n != maxPools+3 {
+		t.Errorf("closed connections, got: %d, expected: %d", n, maxPools+3)
+	}
+	if len(runner.pools) != 0 {
+		t.Errorf("pools, got: %d, expected: 0", len(runner.pools))
+	}
+}
@@ -0,0 +1,137 @@
+// Package shell provides a runner that executes local commands.
+package shell
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akornatskyy/scheduler/internal/domain"
+)
+
+const (
+	// maxOutput limits how much of stdout and stderr each is kept, so both
+	// fit into a job history message.
+	maxOutput = 384
+	waitDelay = time.Second
+)
+
+// inherited lists the server environment variables passed to a command,
+// the rest, e.g. DSN, are not exposed to it.
+var inherited = []string{"PATH", "HOME", "LANG", "TZ"}
+
+type shellRunner struct {
+	environ []string
+}
+
+func NewRunner() domain.Runner {
+	// not nil, otherwise a command inherits the whole environment
+	environ := []string{}
+	for _, name := range inherited {
+		if v, ok := os.LookupEnv(name); ok {
+			environ = append(environ, name+"="+v)
+		}
+	}
+	return &shellRunner{
+		environ: environ,
+	}
+}
+
+func (runner *shellRunner) Run(ctx context.Context, a *domain.Action) (*domain.RunResult, error) {
+	c := a.Command
+	cmd := exec.CommandContext(ctx, c.Path, c.Args...)
+	cmd.Dir = c.Dir
+	cmd.Env = slices.Clone(runner.environ)
+	for _, p := range c.Env {
+		cmd.Env = append(cmd.Env, p.Name+"="+p.Value)
+	}
+	if c.Stdin != "" {
+		cmd.Stdin = strings.NewReader(c.Stdin)
+	}
+	stdout := &limitedBuffer{n: maxOutput}
+	stderr := &limitedBuffer{n: maxOutput}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+
+	started := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(started).Round(time.Millisecond)
+	if cmd.ProcessState == nil {
+		log.Printf("%s - %s", c.Path, err)
+		return nil, err
+	}
+	code := cmd.ProcessState.ExitCode()
+	log.Printf("%s - %d %s", c.Path, code, elapsed)
+
+	res := &domain.RunResult{
+		Code:    code,
+		Message: fmt.Sprintf("%d %s", code, summary(elapsed, stdout, stderr)),
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return res, ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return res, err
+		}
+		return res, &domain.RunError{
+			Code: code,
+			Err:  errors.New(summary(elapsed, stdout, stderr)),
+		}
+	}
+	return res, nil
+}
+
+func summary(elapsed time.Duration, stdout, stderr *limitedBuffer) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "exited after %s", elapsed)
+	if stdout.Len() > 0 {
+		b.WriteString("\nstdout: ")
+		b.WriteString(stdout.String())
+	}
+	if stderr.Len() > 0 {
+		b.WriteString("\nstderr: ")
+		b.WriteString(stderr.String())
+	}
+	return b.String()
+}
+
+// limitedBuffer keeps up to n first bytes written and silently discards
+// the rest.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	n         int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	l := len(p)
+	if r := b.n - b.buf.Len(); r < l {
+		b.truncated = true
+		p = p[:max(r, 0)]
+	}
+	b.buf.Write(p)
+	return l, nil
+}
+
+func (b *limitedBuffer) Len() int {
+	return b.buf.Len()
+}
+
+func (b *limitedBuffer) String() string {
+	s := domain.TruncateMessage(strings.TrimSpace(b.buf.String()), b.n)
+	if b.truncated {
+		s += "..."
+	}
+	return s
+}
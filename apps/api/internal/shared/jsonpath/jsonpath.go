@@ -0,0 +1,118 @@
+// Package jsonpath implements a subset of JSONPath sufficient to address
+// a single value in a decoded JSON document, e.g. `$.data.items[0].id` or
+// `$['content-type']`.
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var errRoot = errors.New("must start with '$'")
+
+type segment struct {
+	name  string
+	index int
+}
+
+// Path is a compiled JSONPath expression.
+type Path []segment
+
+// Parse compiles a JSONPath expression.
+func Parse(s string) (Path, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, errRoot
+	}
+	var p Path
+	i := 1
+	for i < len(s) {
+		switch s[i] {
+		case '.':
+			j := i + 1
+			for j < len(s) && s[j] != '.' && s[j] != '[' {
+				j++
+			}
+			if j == i+1 {
+				return nil, fmt.Errorf("empty name at %d", i)
+			}
+			p = append(p, segment{name: s[i+1 : j], index: -1})
+			i = j
+		case '[':
+			j := strings.IndexByte(s[i:], ']')
+			if j < 0 {
+				return nil, fmt.Errorf("unclosed bracket at %d", i)
+			}
+			inner := s[i+1 : i+j]
+			if n := len(inner); n >= 2 && inner[0] == '\'' && inner[n-1] == '\'' {
+				p = append(p, segment{name: inner[1 : n-1], index: -1})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid index %q at %d", inner, i)
+				}
+				p = append(p, segment{index: index})
+			}
+			i += j + 1
+		default:
+			return nil, fmt.Errorf("unexpected %q at %d", s[i], i)
+		}
+	}
+	return p, nil
+}
+
+// Decode parses a JSON document into interface{} keeping numbers as
+// json.Number, so large integers do not lose precision.
+func Decode(data []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after top-level value")
+	}
+	return v, nil
+}
+
+// Lookup returns a value addressed by the path in v, a document decoded
+// by Decode.
+func (p Path) Lookup(v interface{}) (interface{}, bool) {
+	for _, s := range p {
+		if s.index < 0 {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			v, ok = m[s.name]
+			if !ok {
+				return nil, false
+			}
+			continue
+		}
+		a, ok := v.([]interface{})
+		if !ok || s.index >= len(a) {
+			return nil, false
+		}
+		v = a[s.index]
+	}
+	return v, true
+}
+
+// String returns strings as is and JSON encoding for other values, e.g.
+// `true`, `42` or `null`.
+func String(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
@@ -0,0 +1,105 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{
+		"ok": false,
+		"data": {"items": [{"id": "a"}, {"id": "b"}]},
+		"content-type": "json"
+	}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	var testcases = []struct {
+		path     string
+		expected interface{}
+		found    bool
+	}{
+		{`$`, doc, true},
+		{`$.ok`, false, true},
+		{`$.data.items[1].id`, "b", true},
+		{`$['data']['items'][0]['id']`, "a", true},
+		{`$['content-type']`, "json", true},
+		{`$.data.items[2]`, nil, false},
+		{`$.missing`, nil, false},
+		{`$.ok.x`, nil, false},
+		{`$.data[0]`, nil, false},
+	}
+	for _, tt := range testcases {
+		p, err := Parse(tt.path)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.path, err)
+		}
+		actual, found := p.Lookup(doc)
+		if found != tt.found || !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("%s, got: %v %v, expected: %v %v",
+				tt.path, actual, found, tt.expected, tt.found)
+		}
+	}
+}
+
+func TestParseFails(t *testing.T) {
+	var testcases = []string{
+		``, `ok`, `$.`, `$..x`, `$[`, `$[x]`, `$[-1]`, `$x`,
+	}
+	for _, tt := range testcases {
+		if _, err := Parse(tt); err == nil {
+			t.Errorf("%s: expected error", tt)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	var testcases = []struct {
+		v        interface{}
+		expected string
+	}{
+		{"abc", "abc"},
+		{true, "true"},
+		{42.0, "42"},
+		{nil, "null"},
+		{map[string]interface{}{"id": "a"}, `{"id":"a"}`},
+	}
+	for _, tt := range testcases {
+		if actual := String(tt.v); actual != tt.expected {
+			t.Errorf("%v, got: %s, expected: %s", tt.v, actual, tt.expected)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var testcases = []struct {
+		data     string
+		expected interface{}
+	}{
+		{`9007199254740993`, json.Number("9007199254740993")},
+		{`1000000000000000000000`, json.Number("1000000000000000000000")},
+		{`{"n": 1.50}`, map[string]interface{}{"n": json.Number("1.50")}},
+		{` "a" `, "a"},
+	}
+	for _, tt := range testcases {
+		v, err := Decode([]byte(tt.data))
+		if err != nil {
+			t.Fatalf("%s: %s", tt.data, err)
+		}
+		if !reflect.DeepEqual(v, tt.expected) {
+			t.Errorf("%s, got: %v, expected: %v", tt.data, v, tt.expected)
+		}
+	}
+}
+
+func TestDecodeFails(t *testing.T) {
+	var testcases = []string{
+		``, `{`, `{} {}`, `1 x`,
+	}
+	for _, tt := range testcases {
+		if _, err := Decode([]byte(tt)); err == nil {
+			t.Errorf("%q: expected error", tt)
+		}
+	}
+}
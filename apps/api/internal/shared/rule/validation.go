@@ -20,9 +20,62 @@ var (
 			Required().Min(3).Max(36).
 			Pattern(idPattern, idMessage).Build()
 	Schedule = validator.String("schedule").
-			Required().Min(6).Max(64).Build()
+			Required().Min(6).Max(512).Build()
+	Schedules = validator.String("schedules").
+			Required().Min(6).Max(512).Build()
+	Priority = validator.Number("priority").
+			Min(0).Max(100).Build()
+	MaxRuns = validator.Number("maxRuns").
+		Min(0).Max(100).Build()
+	PreviewCount = validator.Number("count").
+			Min(1).Max(100).Build()
+	UpcomingMode = validator.String("mode").
+			Required().
+			Pattern("^(list|histogram)$", "Must be one of 'list' or 'histogram'.").
+			Build()
+	CalendarID = validator.String("calendars").
+			Required().Min(3).Max(36).
+			Pattern(idPattern, idMessage).Build()
+	CalendarDate = validator.String("dates").
+			Required().
+			Pattern("^[0-9]{4}-[0-9]{2}-[0-9]{2}$", "Required to match YYYY-MM-DD format.").
+			Build()
+	TriggerJobID = validator.String("triggers.jobId").
+			Required().Min(3).Max(36).
+			Pattern(idPattern, idMessage).Build()
+	TriggerOutcome = validator.String("triggers.outcome").
+			Required().
+			Pattern("^(completed|failed|any)$", "Must be one of 'completed', 'failed' or 'any'.").
+			Build()
+	Timezone = validator.String("timezone").
+			Max(64).Build()
+	Misfire = validator.String("misfire.policy").
+		Required().
+		Pattern("^(skip|once|all)$", "Must be one of 'skip', 'once' or 'all'.").
+		Build()
+	MisfireLimit = validator.Number("misfire.limit").
+			Min(0).Max(100).Build()
+	Concurrency = validator.String("concurrency.policy").
+			Required().
+			Pattern("^(forbid|allow|replace)$", "Must be one of 'forbid', 'allow' or 'replace'.").
+			Build()
+	ConcurrencyLimit = validator.Number("concurrency.limit").
+				Min(1).Max(100).Build()
 	ActionType = validator.String("type").
-			Required().Exactly(4).Pattern("^HTTP$", "Must be 'HTTP' only.").Build()
+			Required().Max(16).
+			Pattern("^(HTTP|EXEC|SQL|WORKFLOW)$", "Must be one of 'HTTP', 'EXEC', 'SQL' or 'WORKFLOW'.").Build()
+	StepName = validator.String("steps.name").
+			Required().Min(1).Max(64).Build()
+	StepIf = validator.String("steps.if").
+		Max(256).Build()
+	OutputName = validator.String("outputs.name").
+			Required().Max(64).
+			Pattern("^[A-Za-z_][A-Za-z0-9_]*$", "Required to match a template variable name.").
+			Build()
+	OutputJSON = validator.String("outputs.json").
+			Max(128).Build()
+	OutputHeader = validator.String("outputs.header").
+			Max(32).Build()
 	Method = validator.String("method").
 		Min(3).Max(6).
 		Pattern("^(HEAD|GET|POST|PUT|PATCH|DELETE)$", "Must be a valid HTTP verb.").
@@ -35,8 +88,63 @@ var (
 			Required().Max(256).Build()
 	Body = validator.String("body").
 		Max(1024).Build()
+	StatusCode = validator.Number("statusCodes").
+			Min(100).Max(599).Build()
+	AssertionHeaderValue = validator.String("header.value").
+				Max(256).Build()
+	AssertionBody = validator.String("body").
+			Max(256).Build()
+	BodyPattern = validator.String("bodyPattern").
+			Max(256).Build()
+	JSONPath = validator.String("json.name").
+			Required().Max(128).Build()
+	JSONValue = validator.String("json.value").
+			Max(256).Build()
+	SnapshotHeader = validator.String("snapshot.headers").
+			Required().Min(2).Max(32).Build()
+	BodyLimit = validator.Number("bodyLimit").
+			Min(0).Max(8192).Build()
+	RedactPattern = validator.String("redact").
+			Required().Max(256).Build()
+	Path = validator.String("path").
+		Required().Max(256).Build()
+	Arg = validator.String("args").
+		Max(256).Build()
+	Dir = validator.String("dir").
+		Max(256).Build()
+	EnvName = validator.String("env.name").
+		Required().Max(64).
+		Pattern("^[A-Za-z_][A-Za-z0-9_]*$", "Must be a valid environment variable name.").
+		Build()
+	EnvValue = validator.String("env.value").
+			Max(256).Build()
+	Stdin = validator.String("stdin").
+		Max(1024).Build()
+	DSN = validator.String("dsn").
+		Required().Min(3).Max(64).Build()
+	Query = validator.String("query").
+		Required().Max(2048).Build()
 	VariableValue = validator.String("value").
 			Max(1024).Build()
 	RetryCount = validator.Number("retryCount").
 			Min(0).Max(10).Build()
+	Backoff = validator.String("backoff").
+		Max(16).
+		Pattern("^(fixed|linear|exponential)$",
+			"Must be one of 'fixed', 'linear' or 'exponential'.").
+		Build()
+	Jitter = validator.String("jitter").
+		Max(16).
+		Pattern("^(none|full|equal)$", "Must be one of 'none', 'full' or 'equal'.").
+		Build()
+	RetryableCode = validator.String("retryableCodes").
+			Required().
+			Pattern("^([1-5][0-9]{2}|[1-5]xx)$", "Must be a status code or class, e.g. 503 or 5xx.").
+			Build()
+	RetryableError = validator.String("retryableErrors").
+			Required().
+			Pattern("^(timeout|connection|dns)$", "Must be one of 'timeout', 'connection' or 'dns'.").
+			Build()
+	MaxRows = validator.Number("maxRows").
+		Min(0).Max(100).Build()
 )
@@ -70,17 +70,29 @@ const actionRule: Rule<Omit<Action, 'request' | 'retryPolicy'>> = {
   required: ['type'],
 };
 
-const jobInputRule: Rule<Omit<JobInput, 'action'>> = {
+const jobInputRule: Rule<Omit<JobInput, 'action' | 'triggers'>> = {
   type: 'object',
   properties: {
     name: nameRule,
     collectionId: idRule,
     state: {type: 'string', min: 7, max: 8, pattern: /^(enabled|disabled)$/},
-    schedule: {type: 'string', min: 6, max: 64},
+    schedule: {type: 'string', min: 6, max: 512},
   },
   required: ['name', 'collectionId', 'state', 'schedule'],
 };
 
+// A job fired by triggers only has no schedule.
+const triggeredJobInputRule: Rule<Omit<JobInput, 'action' | 'triggers'>> = {
+  type: 'object',
+  properties: {
+    name: nameRule,
+    collectionId: idRule,
+    state: {type: 'string', min: 7, max: 8, pattern: /^(enabled|disabled)$/},
+    schedule: {type: 'string', max: 512},
+  },
+  required: ['name', 'collectionId', 'state'],
+};
+
 export const checkJobInput = (() => {
   // This check matches flat error locations from server,
   // e.g. deadline vs action.retryPolicy.deadline
@@ -89,6 +101,7 @@ export const checkJobInput = (() => {
   const checkRetryPolicy = compile(retryPolicyRule);
   const checkAction = compile(actionRule);
   const checkJobInput = compile(jobInputRule);
+  const checkTriggeredJobInput = compile(triggeredJobInputRule);
 
   return (
     input: JobInput,
@@ -99,7 +112,11 @@ export const checkJobInput = (() => {
     checkRequest(input.action.request, violations);
     checkRetryPolicy(input.action.retryPolicy, violations);
     checkAction(input.action, violations);
-    checkJobInput(input, violations);
+    if (input.triggers?.length) {
+      checkTriggeredJobInput(input, violations);
+    } else {
+      checkJobInput(input, violations);
+    }
 
     const errors = toErrors(violations);
 
@@ -206,6 +206,30 @@ describe('useJob', () => {
     expect(mockNavigate).toHaveBeenCalledWith('/jobs');
   });
 
+  it('updates a job fired by triggers only', async () => {
+    const triggered: JobDefinition = {
+      ...item,
+      schedule: '',
+      triggers: [{jobId: 'upstream', outcome: 'completed'}],
+    };
+    jest.mocked(api.getJob).mockResolvedValue([triggered, etag]);
+    jest
+      .mocked(collectionsApi.listCollections)
+      .mockResolvedValue({items: collections});
+    jest.mocked(api.updateJob).mockResolvedValue();
+    const {result} = await act(async () => renderHook(() => useJob(id)));
+    act(() => result.current.mutate((draft) => (draft.name = 'Updated name')));
+
+    await act(async () => result.current.save());
+
+    expect(result.current.errors).toEqual({});
+    expect(api.updateJob).toHaveBeenCalledWith(
+      id,
+      {name: 'Updated name'},
+      etag,
+    );
+  });
+
   it('sets errors when update fails', async () => {
     jest.mocked(api.getJob).mockResolvedValue([item, etag]);
     jest.mocked(collectionsApi.listCollections).mockResolvedValue({
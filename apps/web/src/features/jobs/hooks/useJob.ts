@@ -103,12 +103,13 @@ export function useJob(id?: string) {
 }
 
 const toInput = (data: JobDefinition): JobInput => {
-  const {name, collectionId, state, schedule, action} = data;
+  const {name, collectionId, state, schedule, triggers, action} = data;
   return {
     name,
     collectionId,
     state,
     schedule,
+    triggers,
     action: {
       type: 'HTTP',
       request: {
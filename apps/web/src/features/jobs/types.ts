@@ -14,6 +14,7 @@ export type JobItem = {
 
 export type JobDefinition = JobItem & {
   updated: string;
+  triggers?: JobTrigger[];
   action: Action;
 };
 
@@ -22,9 +23,16 @@ export type JobInput = {
   collectionId: string;
   state: JobState;
   schedule: string;
+  triggers?: JobTrigger[];
   action: Action;
 };
 
+export type JobTrigger = {
+  jobId: string;
+  outcome?: 'completed' | 'failed' | 'any';
+  delay?: string;
+};
+
 export type Action = {
   type: 'HTTP';
   request: HttpRequest;